@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type annotationsLoadedMsg struct {
+	jobID       int64
+	annotations []Annotation
+	err         error
+}
+
+func fetchAnnotationsCmd(c *GitHubClient, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		annotations, err := c.ListAnnotations(jobID)
+		return annotationsLoadedMsg{jobID: jobID, annotations: annotations, err: err}
+	}
+}
+
+// openAnnotations switches from the log to the annotations of the selected
+// job, loading them unless they were loaded for this job before.
+func (m *model) openAnnotations() tea.Cmd {
+	m.state = stateAnnotations
+	m.statusMsg = ""
+	if m.annotationsJobID == m.selectedJob.ID {
+		return nil
+	}
+	m.annotationsJobID = m.selectedJob.ID
+	m.loading = true
+	return tea.Batch(m.annotationsList.SetItems(nil), fetchAnnotationsCmd(m.client, m.selectedJob.ID))
+}
+
+// annotationRank orders annotation levels by severity, failures first.
+func annotationRank(level string) int {
+	switch level {
+	case "failure":
+		return 0
+	case "warning":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// annotationItems lists the annotations by severity, keeping the order GitHub
+// reported them in within a level. Titles and messages are redacted like the
+// log they come from.
+func annotationItems(annotations []Annotation, redact redactConfig) []list.Item {
+	sorted := append([]Annotation(nil), annotations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return annotationRank(sorted[i].AnnotationLevel) < annotationRank(sorted[j].AnnotationLevel)
+	})
+	items := make([]list.Item, len(sorted))
+	for i, a := range sorted {
+		a.Title = redact.applyLines(a.Title)
+		a.Message = redact.applyLines(a.Message)
+		items[i] = annotationItem{a}
+	}
+	return items
+}
+
+type annotationItem struct{ a Annotation }
+
+func (a annotationItem) FilterValue() string { return a.a.Message }
+
+type annotationDelegate struct{ width int }
+
+func (d annotationDelegate) Height() int                             { return 1 }
+func (d annotationDelegate) Spacing() int                            { return 0 }
+func (d annotationDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d annotationDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	ai, ok := item.(annotationItem)
+	if !ok {
+		return
+	}
+	if index == m.Index() {
+		row := padToWidth("▶ "+formatAnnotationRow(ai.a, d.width, false), d.width)
+		style := lipgloss.NewStyle().
+			Background(colorSelected).
+			Foreground(colorWhite).
+			Bold(true)
+		fmt.Fprint(w, style.Render(row))
+	} else {
+		fmt.Fprint(w, normalItemStyle.Render("  "+formatAnnotationRow(ai.a, d.width, true)))
+	}
+}
+
+// annotationColumns returns the widths of the annotation list columns; the
+// message takes the remaining space.
+func annotationColumns(width int) (levelW, locW, msgW int) {
+	const (
+		cursorW = 2
+		gaps    = 2
+	)
+	levelW = 7
+	locW = min(40, max(12, width/3))
+	msgW = max(8, width-cursorW-levelW-locW-gaps)
+	return
+}
+
+// formatAnnotationRow renders an annotation without the cursor column: its
+// level, colored when styled is set, its location and the first line of its
+// message.
+func formatAnnotationRow(a Annotation, width int, styled bool) string {
+	levelW, locW, msgW := annotationColumns(width)
+	level := a.AnnotationLevel
+	if level == "failure" {
+		level = "error"
+	}
+	level = padRight(level, levelW)
+	if styled {
+		switch a.AnnotationLevel {
+		case "failure":
+			level = styleError.Render(level)
+		case "warning":
+			level = styleWarn.Render(level)
+		default:
+			level = styleDim.Render(level)
+		}
+	}
+	loc := "workflow"
+	if a.hasFile() {
+		loc = a.Path
+		if a.StartLine > 0 {
+			loc = fmt.Sprintf("%s:%d", a.Path, a.StartLine)
+		}
+	}
+	msg := firstLine(a.Message)
+	if a.Title != "" && !strings.HasPrefix(msg, a.Title) {
+		msg = a.Title + ": " + msg
+	}
+	return level + " " + padRight(truncateLeft(loc, locW), locW) + " " + truncate(msg, msgW)
+}
+
+// truncateLeft shortens s to width by cutting its start, which keeps the file
+// name of a long path visible.
+func truncateLeft(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 3 {
+		return string(r[len(r)-width:])
+	}
+	return "..." + string(r[len(r)-width+3:])
+}
+
+// ─── Annotations view ─────────────────────────────────────────────────────────
+
+func (m model) viewAnnotations() string {
+	viewLabel := fmt.Sprintf("Annotations [%d]", len(m.annotationsList.Items()))
+	if m.loading {
+		viewLabel = m.spinner.View() + " Loading annotations…"
+	}
+	appBar := m.renderAppBar(viewLabel)
+
+	var breadcrumb string
+	if m.statusMsg != "" {
+		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
+	} else {
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Actions › Runs › " +
+			truncate(m.selectedRun.Name, m.width/3) + " › " + truncate(m.selectedJob.Name, m.width/3) + " › Annotations")
+	}
+
+	levelW, locW, msgW := annotationColumns(m.width)
+	colHeaders := colHeaderStyle.Render("  " +
+		padRight("LEVEL", levelW) + " " + padRight("LOCATION", locW) + " " + truncate("MESSAGE", msgW))
+
+	var listView string
+	if !m.loading && len(m.annotationsList.Items()) == 0 {
+		listView = styleDim.Render("\n  This job has no annotations")
+		listView += strings.Repeat("\n", max(0, m.height-6))
+	} else {
+		listView = m.annotationsList.View()
+	}
+
+	footer := renderFooter(keyHints(annotationKeys, "enter", "esc/b", "q"))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		appBar,
+		breadcrumb,
+		colHeaders,
+		listView,
+		footer,
+	)
+}
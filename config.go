@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds user preferences read from the config file. Every field is
+// optional; the zero value is the default behaviour.
+type Config struct {
+	// ConfirmQuit asks before quitting while a watched run is in progress or a
+	// dispatch is in flight.
+	ConfirmQuit bool `yaml:"confirm_quit"`
+
+	// RestoreSession reopens the view tgh was in when it last exited.
+	RestoreSession bool `yaml:"restore_session"`
+
+	// TimeFormat controls the age columns of the runs and PR lists.
+	TimeFormat timeFormat `yaml:"time_format"`
+
+	// RefMatch selects how typing filters the dispatch ref picker.
+	RefMatch refMatch `yaml:"ref_match"`
+
+	// RefGroups groups branches in the dispatch ref picker under their prefix
+	// (feature/, release/, ...) as collapsible sections.
+	RefGroups bool `yaml:"ref_groups"`
+
+	// DispatchRefs maps owner/repo (or host/owner/repo) to the ref the dispatch
+	// form starts with instead of the default branch.
+	DispatchRefs map[string]string `yaml:"dispatch_refs"`
+
+	// ServerTime shows GitHub's clock (from API response headers) in the app bar.
+	ServerTime bool `yaml:"server_time"`
+
+	// Redact masks sensitive-looking values in displayed logs, job summaries
+	// and annotations.
+	Redact redactConfig `yaml:"redact"`
+
+	// LogCacheMB bounds the memory used to keep completed job logs for quick
+	// reopening. Unset or 0 uses the default; a negative value disables it.
+	LogCacheMB int `yaml:"log_cache_mb"`
+
+	// Icons selects unicode or ASCII status icons; auto picks ASCII when the
+	// locale isn't UTF-8.
+	Icons iconStyle `yaml:"icons"`
+
+	// Theme overrides colors of the default palette.
+	Theme themeConfig `yaml:"theme"`
+}
+
+// logCacheBytes returns the log cache budget in bytes.
+func (c Config) logCacheBytes() int {
+	if c.LogCacheMB == 0 {
+		return defaultLogCacheMB << 20
+	}
+	return c.LogCacheMB << 20
+}
+
+// dispatchRef returns the configured default dispatch ref for a repository,
+// preferring a host-qualified key over owner/repo. Empty when unset.
+func (c Config) dispatchRef(host, owner, repo string) string {
+	if ref := c.DispatchRefs[host+"/"+owner+"/"+repo]; ref != "" {
+		return ref
+	}
+	return c.DispatchRefs[owner+"/"+repo]
+}
+
+// refMatch selects how filterRefs matches typed text against ref names.
+type refMatch string
+
+const (
+	refMatchContains refMatch = "contains" // case-insensitive substring
+	refMatchFuzzy    refMatch = "fuzzy"    // characters in order, e.g. tf123 → team/feature/ticket-123
+)
+
+// timeFormat selects how relativeTime renders a timestamp.
+type timeFormat string
+
+const (
+	timeCompact  timeFormat = "compact"  // 5m ago, 2h ago, 3d ago
+	timeVerbose  timeFormat = "verbose"  // 5 minutes ago
+	timeAbsolute timeFormat = "absolute" // 2024-05-01 14:03
+)
+
+// width is the column width needed for timestamps in this format.
+func (f timeFormat) width() int {
+	switch f {
+	case timeVerbose:
+		return 14
+	case timeAbsolute:
+		return 16
+	default:
+		return 8
+	}
+}
+
+// configDir returns tgh's config directory: $XDG_CONFIG_HOME/tgh, falling back
+// to ~/.config/tgh.
+func configDir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "tgh"), nil
+}
+
+// configPath returns the location of the config file.
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yml"), nil
+}
+
+// loadConfig reads the config file. A missing file is not an error and yields
+// the defaults.
+func loadConfig() (Config, error) {
+	cfg := Config{TimeFormat: timeCompact, RefMatch: refMatchContains, Icons: iconsAuto}
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	switch cfg.TimeFormat {
+	case "":
+		cfg.TimeFormat = timeCompact
+	case timeCompact, timeVerbose, timeAbsolute:
+	default:
+		return cfg, fmt.Errorf("%s: unknown time_format %q (want compact, verbose or absolute)", path, cfg.TimeFormat)
+	}
+	switch cfg.RefMatch {
+	case "":
+		cfg.RefMatch = refMatchContains
+	case refMatchContains, refMatchFuzzy:
+	default:
+		return cfg, fmt.Errorf("%s: unknown ref_match %q (want contains or fuzzy)", path, cfg.RefMatch)
+	}
+	switch cfg.Icons {
+	case "":
+		cfg.Icons = iconsAuto
+	case iconsAuto, iconsUnicode, iconsASCII:
+	default:
+		return cfg, fmt.Errorf("%s: unknown icons %q (want auto, unicode or ascii)", path, cfg.Icons)
+	}
+	if err := cfg.Theme.validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Redact.Enabled {
+		if err := cfg.Redact.compile(); err != nil {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	dbg("loaded config from %s: %+v", path, cfg)
+	return cfg, nil
+}
+
+// maxRecentRepos bounds the recent repositories list offered by the picker.
+const maxRecentRepos = 10
+
+// recentReposPath returns the file holding recently opened repositories, one
+// host/owner/repo per line, most recent first.
+func recentReposPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "recent_repos"), nil
+}
+
+// loadRecentRepos returns recently opened repositories as host/owner/repo,
+// most recent first. A missing or unreadable file yields an empty list.
+func loadRecentRepos() []string {
+	path, err := recentReposPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var repos []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			repos = append(repos, line)
+		}
+	}
+	return repos
+}
+
+// saveRecentRepo moves repo (host/owner/repo) to the top of the recent list.
+func saveRecentRepo(repo string) error {
+	path, err := recentReposPath()
+	if err != nil {
+		return err
+	}
+	repos := []string{repo}
+	for _, r := range loadRecentRepos() {
+		if r != repo && len(repos) < maxRecentRepos {
+			repos = append(repos, r)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(repos, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// runSummaryTemplate renders a self-contained HTML page (inline CSS, no external
+// assets) so the exported file can be attached to a wiki page or an email.
+var runSummaryTemplate = template.Must(template.New("run").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Run.Name}} #{{.Run.ID}}</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+  h1 { font-size: 1.4em; margin-bottom: 0.2em; }
+  .meta { color: #59636e; margin-bottom: 1.5em; }
+  table { border-collapse: collapse; min-width: 40em; }
+  th, td { text-align: left; padding: 0.4em 0.8em; border-bottom: 1px solid #d1d9e0; }
+  th { background: #f6f8fa; }
+  .success { color: #1a7f37; font-weight: 600; }
+  .failure { color: #d1242f; font-weight: 600; }
+  .in_progress, .queued { color: #9a6700; font-weight: 600; }
+  .neutral { color: #59636e; }
+  a { color: #0969da; }
+</style>
+</head>
+<body>
+<h1>{{.Run.Name}} <span class="{{.RunClass}}">{{.RunStatus}}</span></h1>
+<div class="meta">
+  {{.Repo}} · branch <code>{{.Run.HeadBranch}}</code> · {{.Run.Event}} · {{.Run.CreatedAt.Format "2006-01-02 15:04 MST"}}
+  {{if .Run.HTMLURL}}· <a href="{{.Run.HTMLURL}}">view on GitHub</a>{{end}}
+</div>
+<table>
+  <tr><th>Job</th><th>Status</th><th>Duration</th></tr>
+  {{range .Jobs}}
+  <tr>
+    <td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td>
+    <td class="{{.Class}}">{{.Status}}</td>
+    <td>{{.Duration}}</td>
+  </tr>
+  {{end}}
+</table>
+<p class="meta">Exported by tgh on {{.Generated.Format "2006-01-02 15:04 MST"}}</p>
+</body>
+</html>
+`))
+
+// statusClass maps a status/conclusion pair to a CSS class in runSummaryTemplate.
+func statusClass(status, conclusion string) string {
+	switch {
+	case isRunning(status):
+		return status
+	case conclusion == "success", conclusion == "failure":
+		return conclusion
+	default:
+		return "neutral"
+	}
+}
+
+// exportRunSummaryHTML writes an HTML summary of a run and its jobs to the
+// current directory and returns the absolute path of the written file. An
+// existing file of the same name is left alone.
+func exportRunSummaryHTML(repo string, run WorkflowRun, jobs []Job) (string, error) {
+	type jobRow struct {
+		Name, URL, Status, Class, Duration string
+	}
+	data := struct {
+		Repo      string
+		Run       WorkflowRun
+		RunStatus string
+		RunClass  string
+		Jobs      []jobRow
+		Generated time.Time
+	}{
+		Repo:      repo,
+		Run:       run,
+		RunStatus: statusLabel(run.Status, run.Conclusion),
+		RunClass:  statusClass(run.Status, run.Conclusion),
+		Generated: time.Now(),
+	}
+	for _, j := range jobs {
+		data.Jobs = append(data.Jobs, jobRow{
+			Name:     j.Name,
+			URL:      j.HTMLURL,
+			Status:   statusLabel(j.Status, j.Conclusion),
+			Class:    statusClass(j.Status, j.Conclusion),
+			Duration: jobDuration(j),
+		})
+	}
+
+	path, err := filepath.Abs(fmt.Sprintf("tgh-run-%d.html", run.ID))
+	if err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("%s already exists; move it away to export the run again", path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := runSummaryTemplate.Execute(f, data); err != nil {
+		// Don't leave a partial file behind to block the next export.
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// runLinksText formats the run's URL followed by a Markdown list of its jobs'
+// URLs, for pasting into an incident or standup note. Jobs without a URL are
+// left out; the number of job links included is returned alongside the text.
+func runLinksText(run WorkflowRun, jobs []Job) (string, int) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s): %s\n", run.Name, statusLabel(run.Status, run.Conclusion), run.HTMLURL)
+	n := 0
+	for _, j := range jobs {
+		if j.HTMLURL == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "- %s (%s): %s\n", j.Name, statusLabel(j.Status, j.Conclusion), j.HTMLURL)
+		n++
+	}
+	return b.String(), n
+}
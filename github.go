@@ -3,20 +3,27 @@ package main
 import (
 	"archive/zip"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
@@ -54,18 +61,29 @@ type WorkflowRun struct {
 	Status     string    `json:"status"`
 	Conclusion string    `json:"conclusion"`
 	HeadBranch string    `json:"head_branch"`
+	HeadSHA    string    `json:"head_sha"`
+	WorkflowID int64     `json:"workflow_id"`
 	Event      string    `json:"event"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	HTMLURL    string    `json:"html_url"`
+	Path       string    `json:"path"`
+	HeadCommit struct {
+		Message string `json:"message"`
+	} `json:"head_commit"`
+	Actor struct {
+		Login string `json:"login"`
+	} `json:"actor"`
 }
 
 // Job represents a single job within a workflow run.
 type Job struct {
 	ID          int64     `json:"id"`
+	RunID       int64     `json:"run_id"`
 	Name        string    `json:"name"`
 	Status      string    `json:"status"`
 	Conclusion  string    `json:"conclusion"`
+	CreatedAt   time.Time `json:"created_at"`
 	StartedAt   time.Time `json:"started_at"`
 	CompletedAt time.Time `json:"completed_at"`
 	Steps       []Step    `json:"steps"`
@@ -85,9 +103,168 @@ type Step struct {
 // GitHubClient wraps the go-gh REST client with repo context.
 type GitHubClient struct {
 	rest  *api.RESTClient
+	gql   *api.GraphQLClient
 	host  string
 	owner string
 	repo  string
+
+	mu            sync.Mutex
+	liveStrategy  liveLogStrategy // running-log strategy that worked for this host
+	liveWebDenied bool            // web endpoint rejected us (4xx); don't probe it again
+	serverSeen    bool            // a response carried a Date header
+	serverOffset  time.Duration   // server clock minus local clock
+	rate          *rateLimit      // REST quota from the last response, nil until reported
+	refs          *cachedRefs     // branches and tags for the dispatch form, see ListRefs
+}
+
+// newGitHubClient builds a client whose REST transport records the server's
+// clock from each successful response.
+func newGitHubClient(host, owner, repo string) (*GitHubClient, error) {
+	c := &GitHubClient{host: host, owner: owner, repo: repo}
+	opts := api.ClientOptions{
+		Host:      host,
+		Transport: serverDateTransport{base: http.DefaultTransport, c: c},
+	}
+	rest, err := api.NewRESTClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create GitHub client: %w", err)
+	}
+	gql, err := api.NewGraphQLClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create GitHub client: %w", err)
+	}
+	c.rest, c.gql = rest, gql
+	return c, nil
+}
+
+// serverDateTransport reads the Date header of successful API responses and
+// the rate limit headers of all of them.
+type serverDateTransport struct {
+	base http.RoundTripper
+	c    *GitHubClient
+}
+
+func (t serverDateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.c.recordRateLimit(resp.Header)
+	}
+	if err == nil && resp.StatusCode < 300 {
+		if date, perr := http.ParseTime(resp.Header.Get("Date")); perr == nil {
+			t.c.mu.Lock()
+			t.c.serverSeen = true
+			t.c.serverOffset = time.Until(date)
+			t.c.mu.Unlock()
+		}
+	}
+	return resp, err
+}
+
+// minClockSkew is the smallest offset treated as real skew. The Date header
+// has one-second resolution, so smaller offsets are measurement noise.
+const minClockSkew = 2 * time.Second
+
+// Now returns the current time by the server's clock, as of the last API
+// response, or the local time before any response or when the clocks agree.
+func (c *GitHubClient) Now() time.Time {
+	c.mu.Lock()
+	offset := c.serverOffset
+	c.mu.Unlock()
+	if offset > -minClockSkew && offset < minClockSkew {
+		offset = 0
+	}
+	return time.Now().Add(offset)
+}
+
+// ServerTime returns the server's current time and whether it is known yet.
+func (c *GitHubClient) ServerTime() (time.Time, bool) {
+	c.mu.Lock()
+	seen := c.serverSeen
+	c.mu.Unlock()
+	return c.Now(), seen
+}
+
+// liveLogStrategy identifies how logs of a running job are fetched.
+type liveLogStrategy int
+
+const (
+	liveStrategyUnknown  liveLogStrategy = iota // not probed yet
+	liveStrategyWeb                             // undocumented web /steps endpoint
+	liveStrategyBlob                            // Range requests against the log blob
+	liveStrategyPipeline                        // GHES per-step logs via the pipeline service
+)
+
+func (s liveLogStrategy) String() string {
+	switch s {
+	case liveStrategyWeb:
+		return "web"
+	case liveStrategyBlob:
+		return "blob"
+	case liveStrategyPipeline:
+		return "pipeline"
+	default:
+		return "unknown"
+	}
+}
+
+// LiveStrategy returns the running-log strategy discovered for this host.
+func (c *GitHubClient) LiveStrategy() liveLogStrategy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.liveStrategy
+}
+
+// SetLiveStrategy records the running-log strategy to use for this host.
+func (c *GitHubClient) SetLiveStrategy(s liveLogStrategy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveStrategy = s
+}
+
+// DisableLiveWeb stops using the web endpoint for running logs on this host.
+// The next probe skips straight to the log blob strategies.
+func (c *GitHubClient) DisableLiveWeb() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveWebDenied = true
+	if c.liveStrategy == liveStrategyWeb {
+		c.liveStrategy = liveStrategyUnknown
+	}
+}
+
+// ProbeLiveStrategy determines which running-log strategy works for this host,
+// trying the web endpoint first, then the job's log blob (which on GHES points at
+// the pipeline service). A successful result is remembered for the session so later
+// running jobs skip straight to it. Failures are not remembered: a just-started job
+// may simply not have any logs yet.
+func (c *GitHubClient) ProbeLiveStrategy(job Job) liveLogStrategy {
+	if s := c.LiveStrategy(); s != liveStrategyUnknown {
+		return s
+	}
+	c.mu.Lock()
+	webDenied := c.liveWebDenied
+	c.mu.Unlock()
+
+	strategy := liveStrategyUnknown
+	webOK := false
+	if !webDenied {
+		_, _, ok, err := c.GetLiveJobLogs(job.HTMLURL, 0)
+		webOK = ok && err == nil
+	}
+	if webOK {
+		strategy = liveStrategyWeb
+	} else if blobURL, err := c.GetJobLogBlobURL(job.ID); err == nil && blobURL != "" {
+		if parsePipelineServiceURL(blobURL) != nil {
+			strategy = liveStrategyPipeline
+		} else if err := probeLogBlob(blobURL); err == nil {
+			strategy = liveStrategyBlob
+		}
+	}
+	dbg("ProbeLiveStrategy: host=%s strategy=%s", c.host, strategy)
+	if strategy != liveStrategyUnknown {
+		c.SetLiveStrategy(strategy)
+	}
+	return strategy
 }
 
 // liveHTTPClient is used for requests to GitHub web endpoints.
@@ -136,6 +313,16 @@ func parseRepoURL(arg string) (host, owner, repo string, ok bool) {
 	return
 }
 
+// apiBaseURL returns the REST API root for a host. github.com and GHE.com
+// data-residency tenants ({tenant}.ghe.com) serve the API from an "api."
+// subdomain; GHES serves it under /api/v3 on the instance host.
+func apiBaseURL(host string) string {
+	if auth.IsEnterprise(host) {
+		return "https://" + host + "/api/v3"
+	}
+	return "https://api." + auth.NormalizeHostname(host)
+}
+
 // changeToRepoDir changes the current working directory to the specified repo path.
 func changeToRepoDir(repoPath string) error {
 	absPath, err := filepath.Abs(repoPath)
@@ -145,6 +332,10 @@ func changeToRepoDir(repoPath string) error {
 	return os.Chdir(absPath)
 }
 
+// errRepoNotDetected is returned by NewGitHubClient when the working directory
+// is not inside a repository with a GitHub remote.
+var errRepoNotDetected = errors.New("could not detect GitHub repository")
+
 // NewGitHubClient creates a client scoped to a GitHub repository.
 // The optional argument may be a filesystem path, an HTTPS URL, or a git remote URL.
 // If omitted, the current directory's git remote is used.
@@ -156,11 +347,10 @@ func NewGitHubClient(repoPath ...string) (*GitHubClient, error) {
 
 	// Check if the argument looks like a URL first.
 	if host, owner, repo, ok := parseRepoURL(arg); ok {
-		client, err := api.NewRESTClient(api.ClientOptions{Host: host})
-		if err != nil {
-			return nil, fmt.Errorf("could not create GitHub client: %w", err)
-		}
-		return &GitHubClient{rest: client, host: host, owner: owner, repo: repo}, nil
+		// Map API hosts (api.github.com, api.{tenant}.ghe.com) back to the web
+		// host so token lookup and web URLs use the tenant-scoped name.
+		host = auth.NormalizeHostname(host)
+		return newGitHubClient(host, owner, repo)
 	}
 
 	// Otherwise treat it as a filesystem path.
@@ -172,20 +362,33 @@ func NewGitHubClient(repoPath ...string) (*GitHubClient, error) {
 
 	repo, err := repository.Current()
 	if err != nil {
-		return nil, fmt.Errorf("could not detect GitHub repository: %w\nRun tgh inside a directory with a GitHub remote", err)
+		return nil, fmt.Errorf("%w: %v\nRun tgh inside a directory with a GitHub remote", errRepoNotDetected, err)
 	}
 
-	client, err := api.NewRESTClient(api.ClientOptions{Host: repo.Host})
-	if err != nil {
-		return nil, fmt.Errorf("could not create GitHub client: %w", err)
-	}
+	return newGitHubClient(repo.Host, repo.Owner, repo.Name)
+}
 
-	return &GitHubClient{
-		rest:  client,
-		host:  repo.Host,
-		owner: repo.Owner,
-		repo:  repo.Name,
-	}, nil
+// repoNamePart matches an owner or repository name.
+var repoNamePart = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// NewGitHubClientForRepo creates a client for a repository given as owner/name
+// or host/owner/name, without looking at the working directory. The host
+// defaults to gh's default host.
+func NewGitHubClientForRepo(spec string) (*GitHubClient, error) {
+	parts := strings.Split(spec, "/")
+	if (len(parts) != 2 && len(parts) != 3) || parts[0] == "" {
+		return nil, fmt.Errorf("invalid repository %q: use owner/name or host/owner/name", spec)
+	}
+	for _, part := range parts[len(parts)-2:] {
+		if !repoNamePart.MatchString(part) {
+			return nil, fmt.Errorf("invalid repository %q: use owner/name or host/owner/name", spec)
+		}
+	}
+	if len(parts) == 3 {
+		return newGitHubClient(auth.NormalizeHostname(parts[0]), parts[1], parts[2])
+	}
+	host, _ := auth.DefaultHost()
+	return newGitHubClient(host, parts[0], parts[1])
 }
 
 // ListRuns fetches the 30 most recent workflow runs, merged with any currently
@@ -279,8 +482,70 @@ func (c *GitHubClient) ListJobs(runID int64) ([]Job, error) {
 	return result.Jobs, err
 }
 
+// GetRun returns a single run, for opening runs no longer among the recent ones.
+func (c *GitHubClient) GetRun(runID int64) (WorkflowRun, error) {
+	var run WorkflowRun
+	err := c.rest.Get(fmt.Sprintf("repos/%s/%s/actions/runs/%d", c.owner, c.repo, runID), &run)
+	return run, err
+}
+
+// maxPreviousRuns bounds the earlier runs FindPreviousJob looks through.
+const maxPreviousRuns = 5
+
+// FindPreviousJob finds the job named jobName in an earlier completed run of
+// the same workflow on the same branch, preferring the most recent run where
+// it succeeded. Returns nil with no error when there is none.
+func (c *GitHubClient) FindPreviousJob(run WorkflowRun, jobName string) (*WorkflowRun, *Job, error) {
+	var result struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	path := fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs?status=completed&per_page=20", c.owner, c.repo, run.WorkflowID)
+	if run.HeadBranch != "" {
+		path += "&branch=" + url.QueryEscape(run.HeadBranch)
+	}
+	if err := c.rest.Get(path, &result); err != nil {
+		return nil, nil, err
+	}
+	var fallbackRun *WorkflowRun
+	var fallbackJob *Job
+	checked := 0
+	for _, r := range result.WorkflowRuns {
+		if r.ID == run.ID || !r.CreatedAt.Before(run.CreatedAt) {
+			continue
+		}
+		if checked == maxPreviousRuns {
+			break
+		}
+		checked++
+		jobs, err := c.ListJobs(r.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, j := range jobs {
+			if j.Name != jobName || j.Status != "completed" {
+				continue
+			}
+			if j.Conclusion == "success" {
+				return &r, &j, nil
+			}
+			if fallbackJob == nil {
+				fallbackRun, fallbackJob = &r, &j
+			}
+		}
+	}
+	return fallbackRun, fallbackJob, nil
+}
+
+// GetJob returns a single job, for refreshing one row without listing the run.
+func (c *GitHubClient) GetJob(jobID int64) (Job, error) {
+	var job Job
+	err := c.rest.Get(fmt.Sprintf("repos/%s/%s/actions/jobs/%d", c.owner, c.repo, jobID), &job)
+	return job, err
+}
+
 // GetJobLogs downloads and parses logs for a given job.
-// Handles both plain-text and zip-encoded responses; strips timestamps.
+// Handles plain-text, zip and gzip-encoded responses. Timestamps are kept;
+// callers strip them with processLogLines for display.
 // Returns empty string with no error if job is still running (logs not yet available).
 func (c *GitHubClient) GetJobLogs(jobID int64) (string, error) {
 	path := fmt.Sprintf("repos/%s/%s/actions/jobs/%d/logs", c.owner, c.repo, jobID)
@@ -308,10 +573,17 @@ func (c *GitHubClient) GetJobLogs(jobID int64) (string, error) {
 	if len(data) >= 2 && data[0] == 'P' && data[1] == 'K' {
 		return parseZipLog(data)
 	}
+	if isGzip(data) {
+		return parseGzipLog(data)
+	}
 
-	return processLogLines(string(data)), nil
+	return string(data), nil
 }
 
+// errLiveTransient marks a live log fetch that failed with a server error. The
+// endpoint usually recovers, so callers should retry instead of falling back.
+var errLiveTransient = errors.New("live log endpoint temporarily unavailable")
+
 // GetLiveJobLogs streams live log content using GitHub's undocumented web endpoint:
 //
 //	GET https://github.com/{owner}/{repo}/actions/runs/{runID}/job/{jobID}/steps?change_id={n}
@@ -320,7 +592,10 @@ func (c *GitHubClient) GetJobLogs(jobID int64) (string, error) {
 // returned nextChangeID to receive only new lines. This endpoint is not publicly
 // supported and may break at any time.
 //
-// Returns ("", changeID, false, nil) when the endpoint is not reachable (non-200).
+// Returns ("", changeID, false, nil) when the endpoint rejects the request (4xx,
+// redirect to login) and another strategy should be used.
+// Returns ("", changeID, true, errLiveTransient) on a server error (5xx) that is
+// worth retrying.
 // Returns ("", changeID, true, nil) when reachable but no new content yet.
 func (c *GitHubClient) GetLiveJobLogs(jobHTMLURL string, changeID int) (lines string, nextChangeID int, endpointOK bool, err error) {
 	token, _ := auth.TokenForHost(c.host)
@@ -350,6 +625,9 @@ func (c *GitHubClient) GetLiveJobLogs(jobHTMLURL string, changeID int) (lines st
 
 	dbg("GetLiveJobLogs: status=%d finalURL=%s", resp.StatusCode, resp.Request.URL)
 
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", changeID, true, fmt.Errorf("%w (status %d)", errLiveTransient, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return "", changeID, false, nil
 	}
@@ -450,11 +728,7 @@ func liveAppendItem(out *[]string, item interface{}) {
 func (c *GitHubClient) GetJobLogBlobURL(jobID int64) (string, error) {
 	token, _ := auth.TokenForHost(c.host)
 
-	apiBase := "https://api.github.com"
-	if c.host != "github.com" {
-		apiBase = "https://" + c.host + "/api/v3"
-	}
-	reqURL := fmt.Sprintf("%s/repos/%s/%s/actions/jobs/%d/logs", apiBase, c.owner, c.repo, jobID)
+	reqURL := fmt.Sprintf("%s/repos/%s/%s/actions/jobs/%d/logs", apiBaseURL(c.host), c.owner, c.repo, jobID)
 	dbg("GetJobLogBlobURL: GET %s", reqURL)
 
 	req, err := http.NewRequest("GET", reqURL, nil)
@@ -484,13 +758,93 @@ func (c *GitHubClient) GetJobLogBlobURL(jobID int64) (string, error) {
 	return "", nil
 }
 
-// FetchLogRange fetches bytes from a blob URL starting at offset.
-// Returns ("", sameOffset, nil) when no new content is available (416).
-// The returned content has timestamps stripped. newOffset is offset+len(raw bytes read).
-func FetchLogRange(blobURL string, offset int64) (content string, newOffset int64, err error) {
+// logRange is what FetchLogRange read from a log blob.
+type logRange struct {
+	content string // new log text as read, timestamps and partial lines included
+	offset  int64  // blob offset to continue reading from
+	gzipped bool   // the blob is gzip-encoded
+	full    bool   // content is the whole log rather than the part after offset
+}
+
+// FetchLogRange fetches bytes from a blob URL starting at offset. gzipped
+// carries over what an earlier call learned about the blob's encoding.
+// Returns an empty content at the same offset when no new content is
+// available (416).
+func FetchLogRange(blobURL string, offset int64, gzipped bool) (logRange, error) {
+	none := logRange{offset: offset, gzipped: gzipped}
+	data, err := fetchBlobBytes(blobURL, offset)
+	if err != nil || data == nil {
+		return none, err
+	}
+
+	// Running-job blobs are plain text, but be safe: reject zip data in Range responses.
+	if len(data) >= 2 && data[0] == 'P' && data[1] == 'K' {
+		dbg("FetchLogRange: zip detected, skipping range approach")
+		return none, fmt.Errorf("blob is zip-encoded, range not supported")
+	}
+	// Some GHES instances store the log gzip-compressed. Output appended as
+	// a further gzip member can be decompressed on its own.
+	if isGzip(data) {
+		text, err := parseGzipLog(data)
+		if err != nil {
+			return none, err
+		}
+		return logRange{content: text, offset: offset + int64(len(data)), gzipped: true}, nil
+	}
+	// A range that starts inside a gzip member can't be decompressed on its
+	// own, so read the whole blob again instead.
+	if gzipped {
+		dbg("FetchLogRange: range at %d is inside a gzip member, fetching the whole blob", offset)
+		data, err := fetchBlobBytes(blobURL, 0)
+		if err != nil || data == nil {
+			return none, err
+		}
+		text, err := parseGzipLog(data)
+		if err != nil {
+			return none, err
+		}
+		return logRange{content: text, offset: int64(len(data)), gzipped: true, full: true}, nil
+	}
+
+	return logRange{content: string(data), offset: offset + int64(len(data))}, nil
+}
+
+// probeLogBlob checks that a log blob can be read in ranges, fetching only its
+// first two bytes to tell a plain or gzip log from a zip archive.
+func probeLogBlob(blobURL string) error {
+	req, err := http.NewRequest("GET", blobURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", "bytes=0-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dbg("probeLogBlob: status=%d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusRequestedRangeNotSatisfiable: // empty so far
+		return nil
+	case http.StatusOK, http.StatusPartialContent:
+	default:
+		return fmt.Errorf("blob fetch: unexpected status %d", resp.StatusCode)
+	}
+	magic := make([]byte, 2)
+	n, _ := io.ReadFull(resp.Body, magic)
+	if n == 2 && magic[0] == 'P' && magic[1] == 'K' {
+		return fmt.Errorf("blob is zip-encoded, range not supported")
+	}
+	return nil
+}
+
+// fetchBlobBytes reads a blob URL from offset on. It returns nil data when
+// the blob has no bytes past offset (416).
+func fetchBlobBytes(blobURL string, offset int64) ([]byte, error) {
 	req, err := http.NewRequest("GET", blobURL, nil)
 	if err != nil {
-		return "", offset, err
+		return nil, err
 	}
 	if offset > 0 {
 		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
@@ -499,7 +853,7 @@ func FetchLogRange(blobURL string, offset int64) (content string, newOffset int6
 	dbg("FetchLogRange: GET offset=%d url=%s", offset, blobURL[:min(80, len(blobURL))])
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", offset, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -507,26 +861,19 @@ func FetchLogRange(blobURL string, offset int64) (content string, newOffset int6
 
 	switch resp.StatusCode {
 	case http.StatusRequestedRangeNotSatisfiable: // 416 — no new bytes
-		return "", offset, nil
+		return nil, nil
 	case http.StatusOK, http.StatusPartialContent: // 200 or 206
 		// fine
 	default:
-		return "", offset, fmt.Errorf("blob fetch: unexpected status %d", resp.StatusCode)
+		return nil, fmt.Errorf("blob fetch: unexpected status %d", resp.StatusCode)
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", offset, err
+		return nil, err
 	}
 	dbg("FetchLogRange: got %d bytes", len(data))
-
-	// Running-job blobs are plain text, but be safe: reject zip data in Range responses.
-	if len(data) >= 2 && data[0] == 'P' && data[1] == 'K' {
-		dbg("FetchLogRange: zip detected, skipping range approach")
-		return "", offset, fmt.Errorf("blob is zip-encoded, range not supported")
-	}
-
-	return processLogLines(string(data)), offset + int64(len(data)), nil
+	return data, nil
 }
 
 func min(a, b int) int {
@@ -536,14 +883,22 @@ func min(a, b int) int {
 	return b
 }
 
+// parseZipLog concatenates the per-step files of a log archive in step order.
+// The files are named after their step number ("2_Build.txt"), but the
+// archive doesn't list them in numeric order, so "10_…" could precede "2_…".
 func parseZipLog(data []byte) (string, error) {
 	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
 	if err != nil {
 		return "", err
 	}
 
+	files := r.File
+	sort.SliceStable(files, func(i, j int) bool {
+		return logFileStep(files[i].Name) < logFileStep(files[j].Name)
+	})
+
 	var sb strings.Builder
-	for _, f := range r.File {
+	for _, f := range files {
 		rc, err := f.Open()
 		if err != nil {
 			continue
@@ -553,11 +908,46 @@ func parseZipLog(data []byte) (string, error) {
 		if err != nil {
 			continue
 		}
-		sb.WriteString(processLogLines(string(content)))
+		sb.Write(content)
 	}
 	return sb.String(), nil
 }
 
+// logFileStep returns the step number a log archive file name starts with;
+// files without one sort after the numbered ones.
+func logFileStep(name string) int {
+	base := path.Base(name)
+	end := 0
+	for end < len(base) && base[end] >= '0' && base[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(base[:end])
+	if err != nil {
+		return math.MaxInt
+	}
+	return n
+}
+
+// isGzip reports whether data starts with the gzip magic bytes.
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// parseGzipLog decompresses a gzip-encoded log, including logs made of
+// several concatenated gzip members.
+func parseGzipLog(data []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("decompressing log: %w", err)
+	}
+	return string(content), nil
+}
+
 // processLogLines strips GitHub Actions timestamp prefixes from each log line.
 // Timestamps look like: "2024-01-01T00:00:00.0000000Z "
 func processLogLines(content string) string {
@@ -597,121 +987,941 @@ func OpenInBrowser(url string) error {
 	return exec.Command(cmd, args...).Start()
 }
 
-// RerunFailedJobs triggers a re-run of only failed jobs in a workflow run.
-func (c *GitHubClient) RerunFailedJobs(runID int64) error {
-	return c.rest.Post(
-		fmt.Sprintf("repos/%s/%s/actions/runs/%d/rerun-failed-jobs", c.owner, c.repo, runID),
-		nil, nil,
-	)
+// RunTiming is the timing summary of a workflow run from the run timing endpoint.
+// Billable is keyed by runner OS (UBUNTU, MACOS, WINDOWS) and is empty for
+// repositories that are not billed, e.g. public repositories or GHES.
+type RunTiming struct {
+	Billable map[string]struct {
+		TotalMS int64 `json:"total_ms"`
+		Jobs    int   `json:"jobs"`
+	} `json:"billable"`
+	RunDurationMS int64 `json:"run_duration_ms"`
 }
 
-// RerunAll triggers a re-run of all jobs in a workflow run.
-func (c *GitHubClient) RerunAll(runID int64) error {
-	return c.rest.Post(
-		fmt.Sprintf("repos/%s/%s/actions/runs/%d/rerun", c.owner, c.repo, runID),
-		nil, nil,
-	)
+// BillableMS returns the billable time summed over all runner OSes.
+func (t RunTiming) BillableMS() int64 {
+	var total int64
+	for _, b := range t.Billable {
+		total += b.TotalMS
+	}
+	return total
 }
 
-// ─── Pull Requests ────────────────────────────────────────────────────────────
+// GetRunTiming fetches billable and wall-clock timing for a workflow run.
+func (c *GitHubClient) GetRunTiming(runID int64) (*RunTiming, error) {
+	var timing RunTiming
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/timing", c.owner, c.repo, runID),
+		&timing,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &timing, nil
+}
 
-// PullRequest represents a GitHub pull request.
-type PullRequest struct {
-	Number    int       `json:"number"`
-	Title     string    `json:"title"`
-	State     string    `json:"state"`
-	Draft     bool      `json:"draft"`
-	UpdatedAt time.Time `json:"updated_at"`
-	HTMLURL   string    `json:"html_url"`
-	User      struct {
-		Login string `json:"login"`
-	} `json:"user"`
-	Head struct {
-		SHA string `json:"sha"`
-		Ref string `json:"ref"`
-	} `json:"head"`
+// ActionsURL returns the web URL of the repository's Actions tab.
+// GHES hosts use the same path layout as github.com.
+func (c *GitHubClient) ActionsURL() string {
+	return fmt.Sprintf("https://%s/%s/%s/actions", c.host, c.owner, c.repo)
 }
 
-// ListPullRequests returns open pull requests sorted by most-recently-updated.
-func (c *GitHubClient) ListPullRequests() ([]PullRequest, error) {
-	var result []PullRequest
-	err := c.rest.Get(
-		fmt.Sprintf("repos/%s/%s/pulls?state=open&per_page=50&sort=updated&direction=desc", c.owner, c.repo),
-		&result,
-	)
-	return result, err
+// FileURL returns the web URL of path at commit sha, anchored at line when > 0.
+func (c *GitHubClient) FileURL(sha, path string, line int) string {
+	u := fmt.Sprintf("https://%s/%s/%s/blob/%s/%s", c.host, c.owner, c.repo, sha, strings.TrimPrefix(path, "/"))
+	if line > 0 {
+		u += fmt.Sprintf("#L%d", line)
+	}
+	return u
 }
 
-// ListRunsForPR fetches workflow runs associated with a specific commit SHA.
-func (c *GitHubClient) ListRunsForPR(headSHA string) ([]WorkflowRun, error) {
-	var result struct {
-		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+// WorkflowFileURL returns the web URL of the workflow file as it was at the
+// run's head commit, falling back to the head branch when the SHA is missing.
+// Returns "" when the run has no workflow file path (e.g. dynamic runs).
+func (c *GitHubClient) WorkflowFileURL(run WorkflowRun) string {
+	// Runs of reusable or dynamic workflows may carry an @ref suffix.
+	path, _, _ := strings.Cut(run.Path, "@")
+	if !strings.HasPrefix(path, ".github/") {
+		return ""
 	}
-	err := c.rest.Get(
-		fmt.Sprintf("repos/%s/%s/actions/runs?head_sha=%s&per_page=50", c.owner, c.repo, headSHA),
-		&result,
-	)
-	if err != nil {
-		return nil, err
+	ref := run.HeadSHA
+	if ref == "" {
+		ref = run.HeadBranch
 	}
-	sort.Slice(result.WorkflowRuns, func(i, j int) bool {
-		return result.WorkflowRuns[i].UpdatedAt.After(result.WorkflowRuns[j].UpdatedAt)
-	})
-	return result.WorkflowRuns, nil
+	if ref == "" {
+		return ""
+	}
+	return c.FileURL(ref, path, 0)
 }
 
-// ─── Workflow dispatch ────────────────────────────────────────────────────────
+// sourceLocation is a file and line referenced by an error annotation.
+type sourceLocation struct {
+	Path string
+	Line int
+}
 
-// Workflow represents a GitHub Actions workflow file.
-type Workflow struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Path  string `json:"path"`
-	State string `json:"state"` // "active", "disabled_manually", etc.
+// Annotation is an error, warning or notice a job reported, e.g. with an
+// ::error command or by a problem matcher.
+type Annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	AnnotationLevel string `json:"annotation_level"` // notice, warning or failure
+	Title           string `json:"title"`
+	Message         string `json:"message"`
 }
 
-// WorkflowInput represents a single input defined in a workflow_dispatch trigger.
-type WorkflowInput struct {
-	Name        string
-	Description string
-	Required    bool
-	Default     string
-	Type        string   // "string", "boolean", "choice", "environment"
-	Options     []string // for "choice" type, in YAML order
+// hasFile reports whether the annotation points at a file. Annotations without
+// one are attached to the workflow file itself.
+func (a Annotation) hasFile() bool {
+	return a.Path != "" && a.Path != ".github"
 }
 
-// ListWorkflows returns all active workflows in the repository.
-func (c *GitHubClient) ListWorkflows() ([]Workflow, error) {
-	var result struct {
-		Workflows []Workflow `json:"workflows"`
-	}
-	if err := c.rest.Get(
-		fmt.Sprintf("repos/%s/%s/actions/workflows?per_page=100", c.owner, c.repo),
-		&result,
-	); err != nil {
+// ListAnnotations returns the annotations of a job. A job's ID doubles as its
+// check run ID.
+func (c *GitHubClient) ListAnnotations(jobID int64) ([]Annotation, error) {
+	var annotations []Annotation
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/check-runs/%d/annotations?per_page=100", c.owner, c.repo, jobID),
+		&annotations,
+	)
+	return annotations, err
+}
+
+// GetErrorAnnotation returns the location of the first failure annotation on a
+// job that points at a file.
+// Returns nil with no error when no annotation has a file.
+func (c *GitHubClient) GetErrorAnnotation(jobID int64) (*sourceLocation, error) {
+	annotations, err := c.ListAnnotations(jobID)
+	if err != nil {
 		return nil, err
 	}
-	var active []Workflow
-	for _, w := range result.Workflows {
-		if w.State == "active" {
-			active = append(active, w)
+	for _, a := range annotations {
+		if a.AnnotationLevel == "failure" && a.hasFile() {
+			return &sourceLocation{Path: c.repoRelativePath(a.Path), Line: a.StartLine}, nil
 		}
 	}
-	return active, nil
+	return nil, nil
 }
 
-// GetDefaultBranch returns the repository's default branch name.
-func (c *GitHubClient) GetDefaultBranch() (string, error) {
-	var repo struct {
-		DefaultBranch string `json:"default_branch"`
+// GetJobSummary returns the markdown summary of a job from its check run's
+// output (title, summary and text). Returns "" when the job has none.
+func (c *GitHubClient) GetJobSummary(jobID int64) (string, error) {
+	var check struct {
+		Output struct {
+			Title   string `json:"title"`
+			Summary string `json:"summary"`
+			Text    string `json:"text"`
+		} `json:"output"`
+	}
+	err := c.rest.Get(fmt.Sprintf("repos/%s/%s/check-runs/%d", c.owner, c.repo, jobID), &check)
+	if err != nil {
+		return "", err
 	}
-	err := c.rest.Get(fmt.Sprintf("repos/%s/%s", c.owner, c.repo), &repo)
-	return repo.DefaultBranch, err
+	var parts []string
+	if t := strings.TrimSpace(check.Output.Title); t != "" {
+		parts = append(parts, "# "+t)
+	}
+	for _, s := range []string{check.Output.Summary, check.Output.Text} {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
 }
 
-// TriggerWorkflowDispatch triggers a workflow_dispatch event on the given ref with optional inputs.
-func (c *GitHubClient) TriggerWorkflowDispatch(workflowID int64, ref string, inputs map[string]string) error {
-	if inputs == nil {
+// repoRelativePath strips the runner workspace prefix
+// (e.g. /home/runner/work/{repo}/{repo}/) from absolute paths reported by tools.
+func (c *GitHubClient) repoRelativePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return path
+	}
+	workspace := "/" + c.repo + "/" + c.repo + "/"
+	if i := strings.Index(path, workspace); i >= 0 {
+		return path[i+len(workspace):]
+	}
+	return path
+}
+
+// parseErrorCommand finds the first ::error workflow command in a log that
+// carries a file parameter, e.g. "::error file=app.js,line=10,col=15::Missing semicolon".
+func parseErrorCommand(logText string) *sourceLocation {
+	for _, line := range strings.Split(logText, "\n") {
+		idx := strings.Index(line, "::error ")
+		if idx < 0 {
+			continue
+		}
+		params := line[idx+len("::error "):]
+		if end := strings.Index(params, "::"); end >= 0 {
+			params = params[:end]
+		}
+		var loc sourceLocation
+		for _, kv := range strings.Split(params, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(kv), "=")
+			switch key {
+			case "file":
+				loc.Path = value
+			case "line":
+				loc.Line, _ = strconv.Atoi(value)
+			}
+		}
+		if loc.Path != "" {
+			return &loc
+		}
+	}
+	return nil
+}
+
+// ChecksRollup summarizes all check runs and commit statuses on a commit, like
+// the merge box of a pull request.
+type ChecksRollup struct {
+	Total     int
+	Failed    int
+	Pending   int
+	Cancelled int // cancelled, skipped or stale: finished without passing
+}
+
+// State returns "failure", "pending", "cancelled" or "success" for the rollup
+// as a whole. A failure wins over pending checks since the PR can no longer go
+// green.
+func (r ChecksRollup) State() string {
+	switch {
+	case r.Failed > 0:
+		return "failure"
+	case r.Pending > 0:
+		return "pending"
+	case r.Cancelled > 0:
+		return "cancelled"
+	default:
+		return "success"
+	}
+}
+
+// maxCheckRunPages caps the check runs GetChecksRollup reads at 1000.
+const maxCheckRunPages = 10
+
+// GetChecksRollup combines the check runs and legacy commit statuses on sha.
+func (c *GitHubClient) GetChecksRollup(sha string) (ChecksRollup, error) {
+	var rollup ChecksRollup
+	for page := 1; page <= maxCheckRunPages; page++ {
+		var checks struct {
+			CheckRuns []struct {
+				Status     string `json:"status"`
+				Conclusion string `json:"conclusion"`
+			} `json:"check_runs"`
+		}
+		path := fmt.Sprintf("repos/%s/%s/commits/%s/check-runs?per_page=100&page=%d", c.owner, c.repo, sha, page)
+		if err := c.rest.Get(path, &checks); err != nil {
+			return rollup, err
+		}
+		for _, cr := range checks.CheckRuns {
+			rollup.addCheckRun(cr.Status, cr.Conclusion)
+		}
+		if len(checks.CheckRuns) < 100 {
+			break
+		}
+	}
+
+	var status struct {
+		Statuses []struct {
+			State string `json:"state"`
+		} `json:"statuses"`
+	}
+	err := c.rest.Get(fmt.Sprintf("repos/%s/%s/commits/%s/status", c.owner, c.repo, sha), &status)
+	if err != nil {
+		return rollup, err
+	}
+	for _, st := range status.Statuses {
+		rollup.addStatus(st.State)
+	}
+	return rollup, nil
+}
+
+// addCheckRun counts a check run by its REST status and conclusion.
+func (r *ChecksRollup) addCheckRun(status, conclusion string) {
+	r.Total++
+	switch {
+	case status != "completed":
+		r.Pending++
+	case conclusion == "failure", conclusion == "timed_out",
+		conclusion == "action_required", conclusion == "startup_failure":
+		r.Failed++
+	case conclusion == "cancelled", conclusion == "skipped", conclusion == "stale":
+		r.Cancelled++
+	}
+}
+
+// addStatus counts a legacy commit status by its REST state.
+func (r *ChecksRollup) addStatus(state string) {
+	r.Total++
+	switch state {
+	case "pending", "expected":
+		r.Pending++
+	case "failure", "error":
+		r.Failed++
+	}
+}
+
+// prStatusQuery fetches the mergeability and the check runs and commit
+// statuses on the head commit of the open pull requests ListPullRequests
+// returns. Only the first 100 contexts of a commit are included; pageInfo
+// tells ListPRStatuses when there are more.
+const prStatusQuery = `query($owner: String!, $repo: String!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequests(states: OPEN, first: 50, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes {
+        number
+        headRefOid
+        mergeable
+        commits(last: 1) {
+          nodes {
+            commit {
+              statusCheckRollup {
+                contexts(first: 100) {
+                  totalCount
+                  pageInfo { hasNextPage }
+                  nodes {
+                    __typename
+                    ... on CheckRun { status conclusion }
+                    ... on StatusContext { state }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// PRStatus is the checks rollup and mergeability of an open pull request.
+type PRStatus struct {
+	Number    int
+	HeadSHA   string
+	Checks    ChecksRollup
+	Mergeable *bool // nil while GitHub is still computing it
+}
+
+// ListPRStatuses returns the checks rollup and mergeability of every open
+// pull request. It is a single GraphQL request, where the REST API takes two
+// requests per commit for the rollup and one per PR for its mergeability.
+// A commit with more than 100 checks is counted with GetChecksRollup instead,
+// so the PR list agrees with the PR's own checks view.
+func (c *GitHubClient) ListPRStatuses() ([]PRStatus, error) {
+	var resp struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes []struct {
+					Number     int
+					HeadRefOid string
+					Mergeable  string
+					Commits    struct {
+						Nodes []struct {
+							Commit struct {
+								StatusCheckRollup *struct {
+									Contexts struct {
+										TotalCount int
+										PageInfo   struct {
+											HasNextPage bool
+										}
+										Nodes []struct {
+											Typename   string `json:"__typename"`
+											Status     string
+											Conclusion string
+											State      string
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	err := c.gql.Do(prStatusQuery, map[string]interface{}{"owner": c.owner, "repo": c.repo}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []PRStatus
+	for _, pr := range resp.Repository.PullRequests.Nodes {
+		st := PRStatus{Number: pr.Number, HeadSHA: pr.HeadRefOid}
+		switch pr.Mergeable {
+		case "MERGEABLE", "CONFLICTING":
+			mergeable := pr.Mergeable == "MERGEABLE"
+			st.Mergeable = &mergeable
+		}
+		for _, commit := range pr.Commits.Nodes {
+			if commit.Commit.StatusCheckRollup == nil {
+				continue // no checks at all
+			}
+			contexts := commit.Commit.StatusCheckRollup.Contexts
+			if contexts.PageInfo.HasNextPage {
+				rollup, err := c.GetChecksRollup(pr.HeadRefOid)
+				if err == nil {
+					st.Checks = rollup
+					continue
+				}
+				dbg("GetChecksRollup %s: %v", pr.HeadRefOid, err)
+			}
+			// GraphQL enums are the REST values in upper case.
+			for _, ctx := range contexts.Nodes {
+				switch ctx.Typename {
+				case "CheckRun":
+					st.Checks.addCheckRun(strings.ToLower(ctx.Status), strings.ToLower(ctx.Conclusion))
+				case "StatusContext":
+					st.Checks.addStatus(strings.ToLower(ctx.State))
+				}
+			}
+			// Count the checks that could not be read as pending rather than
+			// let the ones past the first page show as passing.
+			if rest := contexts.TotalCount - st.Checks.Total; rest > 0 {
+				st.Checks.Total += rest
+				st.Checks.Pending += rest
+			}
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// RerunFailedJobs triggers a re-run of only failed jobs in a workflow run.
+func (c *GitHubClient) RerunFailedJobs(runID int64) error {
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/rerun-failed-jobs", c.owner, c.repo, runID),
+		nil, nil,
+	)
+}
+
+// RerunAll triggers a re-run of all jobs in a workflow run.
+func (c *GitHubClient) RerunAll(runID int64) error {
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/rerun", c.owner, c.repo, runID),
+		nil, nil,
+	)
+}
+
+// RerunJob triggers a re-run of a single job and the jobs that depend on it.
+func (c *GitHubClient) RerunJob(jobID int64) error {
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/actions/jobs/%d/rerun", c.owner, c.repo, jobID),
+		nil, nil,
+	)
+}
+
+// RunOnLatest runs a run's workflow again on the current head of the run's
+// branch rather than on the run's own commit. A workflow with a
+// workflow_dispatch trigger is dispatched on the branch with its default
+// inputs. Any other workflow can only be started by a push, so the run of the
+// head commit is re-run instead, when there is one. It returns a status
+// message describing what was started.
+func (c *GitHubClient) RunOnLatest(run WorkflowRun) (string, error) {
+	branch := run.HeadBranch
+	if branch == "" {
+		return "", fmt.Errorf("run has no branch")
+	}
+	path, _, _ := strings.Cut(run.Path, "@")
+	data, err := c.getFileContent(path, branch)
+	if err != nil {
+		return "", err
+	}
+	dispatchable, err := workflowHasTrigger(data, "workflow_dispatch")
+	if err != nil {
+		return "", err
+	}
+	if dispatchable {
+		inputs, err := parseWorkflowInputs(data)
+		if err != nil {
+			return "", err
+		}
+		for _, in := range inputs {
+			if in.Required && in.Default == "" {
+				return "", fmt.Errorf("input %q is required; dispatch the workflow with d instead", in.Name)
+			}
+		}
+		if err := c.TriggerWorkflowDispatch(run.WorkflowID, branch, nil); err != nil {
+			return "", err
+		}
+		return "Dispatched " + run.Name + " on the latest commit of " + branch + " with its default inputs", nil
+	}
+
+	var b struct {
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := c.rest.Get(fmt.Sprintf("repos/%s/%s/branches/%s", c.owner, c.repo, url.PathEscape(branch)), &b); err != nil {
+		return "", err
+	}
+	head := b.Commit.SHA
+	target := run
+	if head != run.HeadSHA {
+		var result struct {
+			WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+		}
+		if err := c.rest.Get(
+			fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs?head_sha=%s&per_page=1", c.owner, c.repo, run.WorkflowID, head),
+			&result,
+		); err != nil {
+			return "", err
+		}
+		if len(result.WorkflowRuns) == 0 {
+			return "", fmt.Errorf("%s has no workflow_dispatch trigger and no run on %s (%s) yet", run.Name, branch, head[:min(7, len(head))])
+		}
+		target = result.WorkflowRuns[0]
+	}
+	if target.Status != "completed" {
+		return run.Name + " is already running on the latest commit of " + branch, nil
+	}
+	if err := c.RerunAll(target.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Re-running %s on the latest commit of %s (%s)", run.Name, branch, head[:min(7, len(head))]), nil
+}
+
+// PendingDeployment is an environment deployment of a run waiting for review.
+type PendingDeployment struct {
+	Environment struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"environment"`
+	CurrentUserCanApprove bool `json:"current_user_can_approve"`
+	Reviewers             []struct {
+		Type     string `json:"type"` // "User" or "Team"
+		Reviewer struct {
+			Login string `json:"login"` // users
+			Slug  string `json:"slug"`  // teams
+		} `json:"reviewer"`
+	} `json:"reviewers"`
+}
+
+// ReviewerNames lists the required reviewers as @login or team slug.
+func (d PendingDeployment) ReviewerNames() []string {
+	names := make([]string, 0, len(d.Reviewers))
+	for _, r := range d.Reviewers {
+		if r.Type == "Team" {
+			names = append(names, r.Reviewer.Slug)
+		} else {
+			names = append(names, "@"+r.Reviewer.Login)
+		}
+	}
+	return names
+}
+
+// ListPendingDeployments returns the deployments of a run waiting for an
+// environment's required reviewers.
+func (c *GitHubClient) ListPendingDeployments(runID int64) ([]PendingDeployment, error) {
+	var deployments []PendingDeployment
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/pending_deployments", c.owner, c.repo, runID),
+		&deployments,
+	)
+	return deployments, err
+}
+
+// ReviewPendingDeployments approves or rejects a run's deployments to the
+// given environments. An empty comment is replaced by a note naming tgh.
+func (c *GitHubClient) ReviewPendingDeployments(runID int64, environmentIDs []int64, approve bool, comment string) error {
+	state := "rejected"
+	if approve {
+		state = "approved"
+	}
+	if comment == "" {
+		comment = "Reviewed from tgh"
+	}
+	payload := struct {
+		EnvironmentIDs []int64 `json:"environment_ids"`
+		State          string  `json:"state"`
+		Comment        string  `json:"comment"`
+	}{environmentIDs, state, comment}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/pending_deployments", c.owner, c.repo, runID),
+		bytes.NewReader(data), nil,
+	)
+}
+
+// CancelRun requests cancellation of an in-progress or queued workflow run.
+func (c *GitHubClient) CancelRun(runID int64) error {
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/cancel", c.owner, c.repo, runID),
+		nil, nil,
+	)
+}
+
+// ─── Artifacts ────────────────────────────────────────────────────────────────
+
+// Artifact is a file bundle uploaded by a workflow run.
+type Artifact struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	SizeInBytes int64     `json:"size_in_bytes"`
+	Expired     bool      `json:"expired"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// errArtifactExpired is returned when GitHub has already deleted an artifact.
+var errArtifactExpired = errors.New("artifact has expired")
+
+// ListArtifacts returns the artifacts of a workflow run.
+func (c *GitHubClient) ListArtifacts(runID int64) ([]Artifact, error) {
+	var result struct {
+		Artifacts []Artifact `json:"artifacts"`
+	}
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/actions/runs/%d/artifacts?per_page=100", c.owner, c.repo, runID),
+		&result,
+	)
+	return result.Artifacts, err
+}
+
+// DownloadArtifact downloads an artifact's zip archive and extracts it into
+// destDir, returning the paths of the extracted files.
+func (c *GitHubClient) DownloadArtifact(id int64, destDir string) ([]string, error) {
+	token, _ := auth.TokenForHost(c.host)
+	reqURL := fmt.Sprintf("%s/repos/%s/%s/actions/artifacts/%d/zip", apiBaseURL(c.host), c.owner, c.repo, id)
+	dbg("DownloadArtifact: GET %s", reqURL)
+
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	// The API redirects to a short-lived blob URL; fetch that without our token.
+	noRedirect := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: 10 * time.Second,
+	}
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusFound:
+	case http.StatusGone:
+		return nil, errArtifactExpired
+	default:
+		return nil, fmt.Errorf("downloading artifact: HTTP %d", resp.StatusCode)
+	}
+
+	// Artifacts can be large, so the download has no overall deadline; it is
+	// only given up when no data arrives for artifactStallTimeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stall := time.AfterFunc(artifactStallTimeout, cancel)
+	defer stall.Stop()
+	blobReq, err := http.NewRequestWithContext(ctx, "GET", resp.Header.Get("Location"), nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := http.DefaultClient.Do(blobReq)
+	if err != nil {
+		return nil, stalledOr(ctx, err)
+	}
+	defer blob.Body.Close()
+	if blob.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading artifact: HTTP %d", blob.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp("", "tgh-artifact-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, stallReader{r: blob.Body, timer: stall})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, stalledOr(ctx, err)
+	}
+	return extractZip(tmp.Name(), destDir)
+}
+
+// artifactStallTimeout is how long an artifact download may go without
+// receiving data before it is given up.
+const artifactStallTimeout = 30 * time.Second
+
+// stallReader pushes back timer on every read that returns data.
+type stallReader struct {
+	r     io.Reader
+	timer *time.Timer
+}
+
+func (s stallReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if n > 0 {
+		s.timer.Reset(artifactStallTimeout)
+	}
+	return n, err
+}
+
+// stalledOr explains err as a stalled download when the stall timer cancelled
+// ctx.
+func stalledOr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("downloading artifact: no data received for %s", artifactStallTimeout)
+	}
+	return err
+}
+
+// extractZip writes the files of the zip archive at zipPath below destDir,
+// rejecting entries that would escape it. Nothing is written when any of the
+// files already exists.
+func extractZip(zipPath, destDir string) ([]string, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	root, err := filepath.Abs(destDir)
+	if err != nil {
+		return nil, err
+	}
+	// Check every entry before writing any, so a conflict leaves no
+	// half-extracted tree behind.
+	seen := make(map[string]bool)
+	for _, f := range r.File {
+		path := filepath.Join(root, f.Name)
+		if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return nil, fmt.Errorf("artifact entry %q escapes the target directory", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			if fi, err := os.Lstat(path); err == nil && !fi.IsDir() {
+				return nil, fmt.Errorf("%s already exists; move it away to download the artifact again", path)
+			}
+			continue
+		}
+		if _, err := os.Lstat(path); err == nil || seen[path] {
+			return nil, fmt.Errorf("%s already exists; move it away to download the artifact again", path)
+		}
+		seen[path] = true
+	}
+	var paths []string
+	for _, f := range r.File {
+		path := filepath.Join(root, f.Name)
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return paths, err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return paths, err
+		}
+		if err := writeZipFile(f, path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeZipFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, f.Mode().Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ─── Pull Requests ────────────────────────────────────────────────────────────
+
+// PullRequest represents a GitHub pull request.
+type PullRequest struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Draft     bool      `json:"draft"`
+	UpdatedAt time.Time `json:"updated_at"`
+	HTMLURL   string    `json:"html_url"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		SHA string `json:"sha"`
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	// Not returned by the list endpoint; filled in from ListPRStatuses. Nil
+	// while GitHub is still computing it in the background.
+	Mergeable *bool `json:"mergeable"`
+}
+
+// conflicted reports whether the PR is known not to be mergeable because of
+// merge conflicts.
+func (pr PullRequest) conflicted() bool {
+	return pr.Mergeable != nil && !*pr.Mergeable
+}
+
+// ListPullRequests returns open pull requests sorted by most-recently-updated.
+func (c *GitHubClient) ListPullRequests() ([]PullRequest, error) {
+	var result []PullRequest
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/pulls?state=open&per_page=50&sort=updated&direction=desc", c.owner, c.repo),
+		&result,
+	)
+	return result, err
+}
+
+// ApprovePR submits an approving review on a pull request.
+func (c *GitHubClient) ApprovePR(number int) error {
+	data, err := json.Marshal(map[string]string{"event": "APPROVE"})
+	if err != nil {
+		return err
+	}
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", c.owner, c.repo, number),
+		bytes.NewReader(data), nil,
+	)
+}
+
+// CommentOnPR adds a comment to a pull request's conversation.
+func (c *GitHubClient) CommentOnPR(number int, body string) error {
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+	return c.rest.Post(
+		fmt.Sprintf("repos/%s/%s/issues/%d/comments", c.owner, c.repo, number),
+		bytes.NewReader(data), nil,
+	)
+}
+
+// ListRunsForPR fetches workflow runs associated with a specific commit SHA.
+func (c *GitHubClient) ListRunsForPR(headSHA string) ([]WorkflowRun, error) {
+	var result struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/actions/runs?head_sha=%s&per_page=50", c.owner, c.repo, headSHA),
+		&result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result.WorkflowRuns, func(i, j int) bool {
+		return result.WorkflowRuns[i].UpdatedAt.After(result.WorkflowRuns[j].UpdatedAt)
+	})
+	return result.WorkflowRuns, nil
+}
+
+// ListRunsForBranch returns the runs of branch together with the recent runs
+// whose branch contains it, newest first. The API's branch filter only matches
+// whole names, so the substring match covers the last 100 runs.
+func (c *GitHubClient) ListRunsForBranch(branch string) ([]WorkflowRun, error) {
+	var exact struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/actions/runs?branch=%s&per_page=50", c.owner, c.repo, url.QueryEscape(branch)),
+		&exact,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var recent struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	if err := c.rest.Get(fmt.Sprintf("repos/%s/%s/actions/runs?per_page=100", c.owner, c.repo), &recent); err != nil {
+		return nil, err
+	}
+	runs := exact.WorkflowRuns
+	seen := make(map[int64]bool, len(runs))
+	for _, r := range runs {
+		seen[r.ID] = true
+	}
+	lower := strings.ToLower(branch)
+	for _, r := range recent.WorkflowRuns {
+		if !seen[r.ID] && strings.Contains(strings.ToLower(r.HeadBranch), lower) {
+			runs = append(runs, r)
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].CreatedAt.After(runs[j].CreatedAt) })
+	return runs, nil
+}
+
+// ─── Workflow dispatch ────────────────────────────────────────────────────────
+
+// Workflow represents a GitHub Actions workflow file.
+type Workflow struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	State string `json:"state"` // "active", "disabled_manually", etc.
+}
+
+// WorkflowInput represents a single input defined in a workflow_dispatch trigger.
+type WorkflowInput struct {
+	Name        string
+	Description string
+	Required    bool
+	Default     string
+	Type        string   // "string", "number", "boolean", "choice", "environment"
+	Options     []string // for "choice" type, in YAML order
+}
+
+// ListWorkflows returns all active workflows in the repository.
+func (c *GitHubClient) ListWorkflows() ([]Workflow, error) {
+	var result struct {
+		Workflows []Workflow `json:"workflows"`
+	}
+	if err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/actions/workflows?per_page=100", c.owner, c.repo),
+		&result,
+	); err != nil {
+		return nil, err
+	}
+	var active []Workflow
+	for _, w := range result.Workflows {
+		if w.State == "active" {
+			active = append(active, w)
+		}
+	}
+	return active, nil
+}
+
+// GetDefaultBranch returns the repository's default branch name.
+func (c *GitHubClient) GetDefaultBranch() (string, error) {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err := c.rest.Get(fmt.Sprintf("repos/%s/%s", c.owner, c.repo), &repo)
+	return repo.DefaultBranch, err
+}
+
+// CurrentLogin returns the login of the authenticated user.
+func (c *GitHubClient) CurrentLogin() (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	err := c.rest.Get("user", &user)
+	return user.Login, err
+}
+
+// TriggerWorkflowDispatch triggers a workflow_dispatch event on the given ref with optional inputs.
+func (c *GitHubClient) TriggerWorkflowDispatch(workflowID int64, ref string, inputs map[string]string) error {
+	if inputs == nil {
 		inputs = map[string]string{}
 	}
 	payload := struct {
@@ -731,48 +1941,168 @@ func (c *GitHubClient) TriggerWorkflowDispatch(workflowID int64, ref string, inp
 	)
 }
 
-// ListRefs returns branch names and tag names for the repository as separate slices.
-func (c *GitHubClient) ListRefs() (branches, tags []string, err error) {
-	type nameOnly struct {
-		Name string `json:"name"`
+// cachedRefs are the repository's branches and tags as of at. A client serves
+// a single repository, so one entry per client caches them per repository.
+type cachedRefs struct {
+	branches, tags []string
+	at             time.Time
+}
+
+// refCacheTTL is how long ListRefs reuses the branches and tags it fetched,
+// so reopening the dispatch form on a repository with many refs is instant.
+const refCacheTTL = 60 * time.Second
+
+// ListRefs returns branch names and tag names for the repository as separate
+// slices, from the cache when it is younger than refCacheTTL unless refresh.
+func (c *GitHubClient) ListRefs(refresh bool) (branches, tags []string, err error) {
+	c.mu.Lock()
+	cached := c.refs
+	c.mu.Unlock()
+	if !refresh && cached != nil && time.Since(cached.at) < refCacheTTL {
+		return cached.branches, cached.tags, nil
+	}
+	branches, tags, err = c.fetchRefs()
+	if err == nil {
+		c.mu.Lock()
+		c.refs = &cachedRefs{branches: branches, tags: tags, at: time.Now()}
+		c.mu.Unlock()
 	}
-	var bs []nameOnly
-	if err = c.rest.Get(
-		fmt.Sprintf("repos/%s/%s/branches?per_page=100", c.owner, c.repo),
-		&bs,
-	); err != nil {
+	return
+}
+
+func (c *GitHubClient) fetchRefs() (branches, tags []string, err error) {
+	if branches, err = c.listRefNames("branches"); err != nil {
 		return
 	}
-	var ts []nameOnly
-	if err = c.rest.Get(
-		fmt.Sprintf("repos/%s/%s/tags?per_page=100", c.owner, c.repo),
-		&ts,
+	tags, err = c.listRefNames("tags")
+	return
+}
+
+// maxRefPages caps the pages of 100 refs fetched per kind, so a repository
+// with an enormous number of branches can't keep the form loading forever.
+const maxRefPages = 20
+
+// listRefNames returns the names of all refs of kind ("branches" or "tags"),
+// page by page until a short page or maxRefPages.
+func (c *GitHubClient) listRefNames(kind string) ([]string, error) {
+	var names []string
+	for page := 1; page <= maxRefPages; page++ {
+		var refs []struct {
+			Name string `json:"name"`
+		}
+		path := fmt.Sprintf("repos/%s/%s/%s?per_page=100&page=%d", c.owner, c.repo, kind, page)
+		if err := c.rest.Get(path, &refs); err != nil {
+			return nil, err
+		}
+		for _, r := range refs {
+			names = append(names, r.Name)
+		}
+		if len(refs) < 100 {
+			return names, nil
+		}
+	}
+	dbg("listRefNames: %s truncated at %d pages", kind, maxRefPages)
+	return names, nil
+}
+
+// EnvVariable is a configuration variable of a deployment environment.
+type EnvVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ListEnvironments returns the names of the repository's deployment environments.
+func (c *GitHubClient) ListEnvironments() ([]string, error) {
+	var result struct {
+		Environments []struct {
+			Name string `json:"name"`
+		} `json:"environments"`
+	}
+	if err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/environments?per_page=100", c.owner, c.repo),
+		&result,
 	); err != nil {
-		return
+		return nil, err
 	}
-	branches = make([]string, len(bs))
-	for i, b := range bs {
-		branches[i] = b.Name
+	names := make([]string, len(result.Environments))
+	for i, e := range result.Environments {
+		names[i] = e.Name
 	}
-	tags = make([]string, len(ts))
-	for i, t := range ts {
-		tags[i] = t.Name
+	return names, nil
+}
+
+// ListEnvironmentVariables returns the variables of a deployment environment.
+// Reading them needs more access than dispatching, so callers should expect
+// a permission error.
+func (c *GitHubClient) ListEnvironmentVariables(env string) ([]EnvVariable, error) {
+	var result struct {
+		Variables []EnvVariable `json:"variables"`
 	}
-	return
+	err := c.rest.Get(
+		fmt.Sprintf("repos/%s/%s/environments/%s/variables?per_page=30", c.owner, c.repo, url.PathEscape(env)),
+		&result,
+	)
+	return result.Variables, err
 }
 
 // GetWorkflowInputs fetches and parses workflow_dispatch inputs from a workflow YAML file.
 // Returns nil inputs (and no error) when the workflow has no workflow_dispatch trigger or no inputs.
+//
+// Only the caller's own inputs are listed, since GitHub rejects a dispatch with
+// any other input. Where the caller forwards one of them to a local reusable
+// workflow (jobs.<id>.uses: ./path, with: name: ${{ inputs.x }}), the callee's
+// workflow_call declaration fills in what the caller's leaves out, such as the
+// description, type and options. Remote reusable workflows and any callee that
+// cannot be fetched or parsed are skipped.
 func (c *GitHubClient) GetWorkflowInputs(workflowPath string) ([]WorkflowInput, error) {
+	data, err := c.getFileContent(workflowPath, "")
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := parseWorkflowInputs(data)
+	if err != nil {
+		return nil, err
+	}
+	callPath := map[string]bool{strings.TrimPrefix(workflowPath, "/"): true}
+	return c.resolveCalledInputs(data, inputs, callPath), nil
+}
+
+// GetRunSchedule returns the cron entry of a scheduled run's workflow that
+// most likely triggered it, reading the workflow file as of the run's commit.
+// Returns nil with no error when the workflow has no schedule.
+func (c *GitHubClient) GetRunSchedule(run WorkflowRun) (*scheduleMatch, error) {
+	path, _, _ := strings.Cut(run.Path, "@")
+	if !strings.HasPrefix(path, ".github/") {
+		return nil, nil
+	}
+	data, err := c.getFileContent(path, run.HeadSHA)
+	if err != nil {
+		return nil, err
+	}
+	crons, err := parseWorkflowSchedules(data)
+	if err != nil {
+		return nil, err
+	}
+	match, ok := likelySchedule(crons, run.CreatedAt)
+	if !ok {
+		return nil, nil
+	}
+	return &match, nil
+}
+
+// getFileContent fetches a file via the contents API, at ref or, when ref is
+// empty, from the repository's default branch.
+func (c *GitHubClient) getFileContent(filePath, ref string) ([]byte, error) {
 	var fileContent struct {
 		Content  string `json:"content"`
 		Encoding string `json:"encoding"`
 	}
-	path := strings.TrimPrefix(workflowPath, "/")
-	if err := c.rest.Get(
-		fmt.Sprintf("repos/%s/%s/contents/%s", c.owner, c.repo, path),
-		&fileContent,
-	); err != nil {
+	path := strings.TrimPrefix(filePath, "/")
+	endpoint := fmt.Sprintf("repos/%s/%s/contents/%s", c.owner, c.repo, path)
+	if ref != "" {
+		endpoint += "?ref=" + url.QueryEscape(ref)
+	}
+	if err := c.rest.Get(endpoint, &fileContent); err != nil {
 		return nil, err
 	}
 	// GitHub API encodes file content as base64 with embedded newlines.
@@ -781,12 +2111,125 @@ func (c *GitHubClient) GetWorkflowInputs(workflowPath string) ([]WorkflowInput,
 	if err != nil {
 		return nil, fmt.Errorf("decode workflow YAML: %w", err)
 	}
-	return parseWorkflowInputs(data)
+	return data, nil
+}
+
+// resolveCalledInputs follows local reusable workflow calls in data and
+// completes the inputs forwarded to them from the callees' workflow_call
+// inputs. callPath holds the workflows on the way to data, guarding against
+// call cycles; a workflow called again from elsewhere is followed again.
+func (c *GitHubClient) resolveCalledInputs(data []byte, inputs []WorkflowInput, callPath map[string]bool) []WorkflowInput {
+	declared := make(map[string]int, len(inputs))
+	for i, inp := range inputs {
+		declared[inp.Name] = i
+	}
+	for _, call := range parseWorkflowCalls(data) {
+		if callPath[call.path] {
+			dbg("resolveCalledInputs: skipping %s (cycle)", call.path)
+			continue
+		}
+		callee, err := c.getFileContent(call.path, "")
+		if err != nil {
+			dbg("resolveCalledInputs: fetch %s: %v", call.path, err)
+			continue
+		}
+		calleeInputs, err := parseTriggerInputs(callee, "workflow_call")
+		if err != nil {
+			dbg("resolveCalledInputs: parse %s: %v", call.path, err)
+			continue
+		}
+		callPath[call.path] = true
+		calleeInputs = c.resolveCalledInputs(callee, calleeInputs, callPath)
+		delete(callPath, call.path)
+		for _, inp := range calleeInputs {
+			i, ok := declared[call.forwards[inp.Name]]
+			if !ok {
+				// Hard-wired, left to its default, or forwarded from an input
+				// the caller doesn't declare.
+				continue
+			}
+			inputs[i] = completeInput(inputs[i], inp)
+		}
+	}
+	return inputs
+}
+
+// completeInput fills in the parts of the caller's input declaration it leaves
+// out from the callee's declaration of the input it is forwarded to. Whether
+// the input is required stays the caller's decision.
+func completeInput(caller, callee WorkflowInput) WorkflowInput {
+	if caller.Description == "" {
+		caller.Description = callee.Description
+	}
+	if caller.Default == "" {
+		caller.Default = callee.Default
+	}
+	if caller.Type == "" {
+		caller.Type = callee.Type
+		caller.Options = callee.Options
+	}
+	return caller
+}
+
+// workflowCall is a local reusable workflow referenced from a job's `uses:` key.
+type workflowCall struct {
+	path     string            // repository-relative path, e.g. ".github/workflows/build.yml"
+	forwards map[string]string // callee input name -> caller input passed as ${{ inputs.<name> }}
+}
+
+// forwardedInput matches a `with:` value that passes a caller input through
+// unchanged.
+var forwardedInput = regexp.MustCompile(`^\$\{\{\s*inputs\.([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}$`)
+
+// parseWorkflowCalls returns the local reusable workflows called by jobs in data.
+// Remote references (owner/repo/path@ref) are ignored since they cannot be resolved
+// against this repository.
+func parseWorkflowCalls(data []byte) []workflowCall {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	jobsNode := findMappingValue(doc.Content[0], "jobs")
+	if jobsNode == nil || jobsNode.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var calls []workflowCall
+	for i := 0; i+1 < len(jobsNode.Content); i += 2 {
+		jobNode := jobsNode.Content[i+1]
+		usesNode := findMappingValue(jobNode, "uses")
+		if usesNode == nil || !strings.HasPrefix(usesNode.Value, "./") {
+			continue
+		}
+		path := strings.TrimPrefix(usesNode.Value, "./")
+		if idx := strings.Index(path, "@"); idx >= 0 {
+			path = path[:idx]
+		}
+		call := workflowCall{path: path, forwards: map[string]string{}}
+		if withNode := findMappingValue(jobNode, "with"); withNode != nil && withNode.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(withNode.Content); j += 2 {
+				if m := forwardedInput.FindStringSubmatch(strings.TrimSpace(withNode.Content[j+1].Value)); m != nil {
+					call.forwards[withNode.Content[j].Value] = m[1]
+				}
+			}
+		}
+		calls = append(calls, call)
+	}
+	return calls
 }
 
 // parseWorkflowInputs extracts workflow_dispatch input definitions from workflow YAML.
 // Uses yaml.Node to preserve the order of inputs as defined in the file.
 func parseWorkflowInputs(data []byte) ([]WorkflowInput, error) {
+	return parseTriggerInputs(data, "workflow_dispatch")
+}
+
+// parseTriggerInputs extracts the input definitions of the given trigger
+// ("workflow_dispatch" or "workflow_call") from workflow YAML.
+func parseTriggerInputs(data []byte, trigger string) ([]WorkflowInput, error) {
 	var doc yaml.Node
 	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, err
@@ -801,7 +2244,7 @@ func parseWorkflowInputs(data []byte) ([]WorkflowInput, error) {
 		return nil, nil
 	}
 
-	wdNode := findMappingValue(onNode, "workflow_dispatch")
+	wdNode := findMappingValue(onNode, trigger)
 	if wdNode == nil || wdNode.Kind != yaml.MappingNode {
 		return nil, nil
 	}
@@ -844,6 +2287,35 @@ func parseWorkflowInputs(data []byte) ([]WorkflowInput, error) {
 	return inputs, nil
 }
 
+// workflowHasTrigger reports whether workflow YAML lists trigger under on:,
+// which may be a single event, a list of events or a mapping.
+func workflowHasTrigger(data []byte, trigger string) (bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return false, nil
+	}
+	onNode := findMappingValue(doc.Content[0], "on")
+	if onNode == nil {
+		return false, nil
+	}
+	switch onNode.Kind {
+	case yaml.ScalarNode:
+		return onNode.Value == trigger, nil
+	case yaml.SequenceNode:
+		for _, n := range onNode.Content {
+			if n.Value == trigger {
+				return true, nil
+			}
+		}
+	case yaml.MappingNode:
+		return findMappingValue(onNode, trigger) != nil, nil
+	}
+	return false, nil
+}
+
 // findMappingValue returns the value node for the given key in a YAML mapping node.
 // Returns nil if the node is not a mapping or the key is not found.
 func findMappingValue(node *yaml.Node, key string) *yaml.Node {
@@ -0,0 +1,573 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		host, want string
+	}{
+		{"github.com", "https://api.github.com"},
+		{"GitHub.com", "https://api.github.com"},
+		{"github.example.com", "https://github.example.com/api/v3"},
+		{"octocorp.ghe.com", "https://api.octocorp.ghe.com"},
+		{"api.octocorp.ghe.com", "https://api.octocorp.ghe.com"},
+	}
+	for _, tt := range tests {
+		if got := apiBaseURL(tt.host); got != tt.want {
+			t.Errorf("apiBaseURL(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+// newTestClient returns a client for owner/repo whose REST requests are served
+// by handler under /api/v3 and GraphQL requests at /api/graphql, as on a GHES
+// host.
+func newTestClient(t *testing.T, handler http.Handler) *GitHubClient {
+	t.Helper()
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+	rest, err := api.NewRESTClient(api.ClientOptions{
+		Host:      strings.TrimPrefix(srv.URL, "https://"),
+		AuthToken: "test",
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gql, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      strings.TrimPrefix(srv.URL, "https://"),
+		AuthToken: "test",
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &GitHubClient{rest: rest, gql: gql, host: "github.example.com", owner: "owner", repo: "repo"}
+}
+
+// refPages serves total branches named b0, b1, ... in pages of per_page,
+// with Link headers like GitHub's, and counts the requests.
+func refPages(total int, requests *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if page < 1 {
+			page = 1
+		}
+		last := (total + perPage - 1) / perPage
+		if page < last {
+			next := *r.URL
+			q := next.Query()
+			q.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = q.Encode()
+			w.Header().Set("Link", fmt.Sprintf(`<https://%s%s>; rel="next", <https://%s%s?page=%d>; rel="last"`,
+				r.Host, next.RequestURI(), r.Host, r.URL.Path, last))
+		}
+		var refs []map[string]string
+		for i := (page - 1) * perPage; i < min(page*perPage, total); i++ {
+			refs = append(refs, map[string]string{"name": fmt.Sprintf("b%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(refs)
+	})
+}
+
+func TestListRefNamesMergesPages(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, refPages(250, &requests))
+	names, err := c.listRefNames("branches")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 250 || requests != 3 {
+		t.Fatalf("got %d names in %d requests, want 250 in 3", len(names), requests)
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("b%d", i); name != want {
+			t.Fatalf("names[%d] = %q, want %q", i, name, want)
+		}
+	}
+}
+
+func TestListRefNamesStopsAtPageCap(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, refPages(100*maxRefPages+50, &requests))
+	names, err := c.listRefNames("tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 100*maxRefPages || requests != maxRefPages {
+		t.Fatalf("got %d names in %d requests, want %d in %d", len(names), requests, 100*maxRefPages, maxRefPages)
+	}
+}
+
+func gzipMember(t *testing.T, text string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchLogRangeGzip(t *testing.T) {
+	var blob []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+	}))
+	defer srv.Close()
+
+	first := gzipMember(t, "step one\n")
+	blob = first
+	got, err := FetchLogRange(srv.URL, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (logRange{content: "step one\n", offset: int64(len(first)), gzipped: true}); got != want {
+		t.Fatalf("first range = %+v, want %+v", got, want)
+	}
+
+	// Output appended as a new member is read from the previous offset.
+	second := gzipMember(t, "step two\n")
+	blob = append(append([]byte{}, first...), second...)
+	got, err = FetchLogRange(srv.URL, got.offset, got.gzipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (logRange{content: "step two\n", offset: int64(len(blob)), gzipped: true}); got != want {
+		t.Fatalf("second range = %+v, want %+v", got, want)
+	}
+
+	// Recompressed as one member, the next range starts inside it and the
+	// whole log is read again.
+	blob = gzipMember(t, "step one\nstep two\nstep three, which makes the member longer\n")
+	got, err = FetchLogRange(srv.URL, int64(len(first)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := logRange{content: "step one\nstep two\nstep three, which makes the member longer\n", offset: int64(len(blob)), gzipped: true, full: true}
+	if got != want {
+		t.Fatalf("mid-member range = %+v, want %+v", got, want)
+	}
+
+	// Nothing past the end of the blob.
+	got, err = FetchLogRange(srv.URL, int64(len(blob)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (logRange{offset: int64(len(blob)), gzipped: true}); got != want {
+		t.Fatalf("range past the end = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseZipLogStepOrder(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range []struct{ name, text string }{
+		{"build/10_x.txt", "ten\n"},
+		{"build/system.txt", "system\n"},
+		{"build/2_y.txt", "two\n"},
+		{"build/1_Set up job.txt", "one\n"},
+	} {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(f.text))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseZipLog(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "one\ntwo\nten\nsystem\n"; got != want {
+		t.Fatalf("parseZipLog = %q, want %q", got, want)
+	}
+}
+
+func TestLogFileStep(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"2_y.txt", 2},
+		{"10_x.txt", 10},
+		{"build/3_Run tests.txt", 3},
+		{"system.txt", math.MaxInt},
+	}
+	for _, tt := range tests {
+		if got := logFileStep(tt.name); got != tt.want {
+			t.Errorf("logFileStep(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestArtifactDir(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"coverage", "coverage"},
+		{"reports/coverage", "coverage"},
+		{".", "artifact-7"},
+		{"..", "artifact-7"},
+		{"", "artifact-7"},
+		{"/", "artifact-7"},
+	}
+	for _, tt := range tests {
+		if got := artifactDir(Artifact{ID: 7, Name: tt.name}); got != tt.want {
+			t.Errorf("artifactDir(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractZipWritesNothingOnConflict(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/c.txt"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(name))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tmp := t.TempDir()
+	zipPath := filepath.Join(tmp, "artifact.zip")
+	if err := os.WriteFile(zipPath, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(tmp, "out")
+	if err := os.MkdirAll(filepath.Join(dest, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dest, "sub", "c.txt"), []byte("mine"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := extractZip(zipPath, dest); err == nil {
+		t.Fatal("extractZip succeeded over an existing file")
+	}
+	for _, name := range []string{"a.txt", "sub/b.txt"} {
+		if _, err := os.Lstat(filepath.Join(dest, name)); err == nil {
+			t.Errorf("%s was written despite the conflict", name)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "sub", "c.txt")); string(data) != "mine" {
+		t.Errorf("existing file was overwritten with %q", data)
+	}
+
+	os.Remove(filepath.Join(dest, "sub", "c.txt"))
+	files, err := extractZip(zipPath, dest)
+	if err != nil || len(files) != 3 {
+		t.Fatalf("extractZip = %d files, %v; want 3 files", len(files), err)
+	}
+}
+
+func TestForwardedInput(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"${{ inputs.environment }}", "environment"},
+		{"${{inputs.env}}", "env"},
+		{"${{ inputs.dry-run }}", "dry-run"},
+		{"${{ inputs.env }}-eu", ""},
+		{"${{ github.ref }}", ""},
+		{"production", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if m := forwardedInput.FindStringSubmatch(tt.value); m != nil {
+			got = m[1]
+		}
+		if got != tt.want {
+			t.Errorf("forwardedInput on %q = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseWorkflowCalls(t *testing.T) {
+	data := []byte(`
+on: workflow_dispatch
+jobs:
+  build:
+    uses: ./.github/workflows/build.yml@main
+    with:
+      environment: ${{ inputs.env }}
+      region: eu-west-1
+  remote:
+    uses: octo/shared/.github/workflows/lint.yml@v1
+    with:
+      level: ${{ inputs.level }}
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make test
+  deploy:
+    uses: ./.github/workflows/deploy.yml
+`)
+	want := []workflowCall{
+		{path: ".github/workflows/build.yml", forwards: map[string]string{"environment": "env"}},
+		{path: ".github/workflows/deploy.yml", forwards: map[string]string{}},
+	}
+	if got := parseWorkflowCalls(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorkflowCalls = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompleteInput(t *testing.T) {
+	callee := WorkflowInput{Name: "environment", Description: "Target", Default: "staging", Type: "choice", Options: []string{"staging", "production"}, Required: true}
+	tests := []struct {
+		name   string
+		caller WorkflowInput
+		want   WorkflowInput
+	}{
+		{
+			"bare declaration",
+			WorkflowInput{Name: "env"},
+			WorkflowInput{Name: "env", Description: "Target", Default: "staging", Type: "choice", Options: []string{"staging", "production"}},
+		},
+		{
+			"caller's own parts kept",
+			WorkflowInput{Name: "env", Description: "Where to", Default: "production", Type: "string", Required: true},
+			WorkflowInput{Name: "env", Description: "Where to", Default: "production", Type: "string", Required: true},
+		},
+	}
+	for _, tt := range tests {
+		if got := completeInput(tt.caller, callee); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: completeInput = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetWorkflowInputsFollowsEveryCall(t *testing.T) {
+	files := map[string]string{
+		".github/workflows/release.yml": `
+on:
+  workflow_dispatch:
+    inputs:
+      env: {}
+      region: {}
+jobs:
+  build:
+    uses: ./.github/workflows/build.yml
+    with:
+      target: ${{ inputs.env }}
+  build-again:
+    uses: ./.github/workflows/build.yml
+    with:
+      target: ${{ inputs.region }}
+`,
+		".github/workflows/build.yml": `
+on:
+  workflow_call:
+    inputs:
+      target:
+        description: Build target
+        type: string
+jobs:
+  loop:
+    uses: ./.github/workflows/release.yml
+`,
+	}
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/api/v3/repos/owner/repo/contents/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"content": base64.StdEncoding.EncodeToString([]byte(content))})
+	}))
+
+	got, err := c.GetWorkflowInputs(".github/workflows/release.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []WorkflowInput{
+		{Name: "env", Description: "Build target", Type: "string"},
+		{Name: "region", Description: "Build target", Type: "string"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetWorkflowInputs = %+v, want %+v", got, want)
+	}
+}
+
+func TestListPRStatusesPagesLargeRollups(t *testing.T) {
+	// PR 1 fits in one page of contexts; PR 2 has more than 100, and the
+	// failing check is past the first page.
+	pr := func(number int, sha string, more bool, total int) map[string]any {
+		nodes := make([]map[string]string, 0, 100)
+		for range min(total, 100) {
+			nodes = append(nodes, map[string]string{"__typename": "CheckRun", "status": "COMPLETED", "conclusion": "SUCCESS"})
+		}
+		return map[string]any{
+			"number": number, "headRefOid": sha, "mergeable": "MERGEABLE",
+			"commits": map[string]any{"nodes": []any{map[string]any{"commit": map[string]any{
+				"statusCheckRollup": map[string]any{"contexts": map[string]any{
+					"totalCount": total,
+					"pageInfo":   map[string]bool{"hasNextPage": more},
+					"nodes":      nodes,
+				}},
+			}}}},
+		}
+	}
+	var fellBack []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/graphql":
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"repository": map[string]any{
+				"pullRequests": map[string]any{"nodes": []any{pr(1, "aaa", false, 2), pr(2, "bbb", true, 101)}},
+			}}})
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			json.NewEncoder(w).Encode(map[string]any{"statuses": []any{}})
+		default:
+			fellBack = append(fellBack, r.URL.Path)
+			runs := []map[string]string{}
+			if r.URL.Query().Get("page") == "1" {
+				for range 100 {
+					runs = append(runs, map[string]string{"status": "completed", "conclusion": "success"})
+				}
+			} else {
+				runs = append(runs, map[string]string{"status": "completed", "conclusion": "failure"})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"check_runs": runs})
+		}
+	}))
+
+	got, err := c.ListPRStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ListPRStatuses returned %d PRs, want 2", len(got))
+	}
+	if want := (ChecksRollup{Total: 2}); got[0].Checks != want {
+		t.Errorf("PR 1 checks = %+v, want %+v", got[0].Checks, want)
+	}
+	if want := (ChecksRollup{Total: 101, Failed: 1}); got[1].Checks != want {
+		t.Errorf("PR 2 checks = %+v, want %+v", got[1].Checks, want)
+	}
+	for _, p := range fellBack {
+		if !strings.Contains(p, "/commits/bbb/") {
+			t.Errorf("fell back to the REST rollup for %s, want only bbb", p)
+		}
+	}
+}
+
+func TestGetChecksRollup(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/status") {
+			json.NewEncoder(w).Encode(map[string]any{"statuses": []map[string]string{{"state": "success"}}})
+			return
+		}
+		// 100 passing check runs, then a second page with a cancelled and a
+		// skipped one.
+		runs := make([]map[string]string, 0, 100)
+		switch r.URL.Query().Get("page") {
+		case "1":
+			for range 100 {
+				runs = append(runs, map[string]string{"status": "completed", "conclusion": "success"})
+			}
+		case "2":
+			runs = append(runs,
+				map[string]string{"status": "completed", "conclusion": "cancelled"},
+				map[string]string{"status": "completed", "conclusion": "skipped"})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"total_count": 102, "check_runs": runs})
+	}))
+
+	got, err := c.GetChecksRollup("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ChecksRollup{Total: 103, Cancelled: 2}); got != want {
+		t.Fatalf("GetChecksRollup = %+v, want %+v", got, want)
+	}
+	if got.State() != "cancelled" {
+		t.Errorf("State() = %q, want cancelled", got.State())
+	}
+}
+
+func TestRunOnLatestEscapesBranch(t *testing.T) {
+	var branchPath string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/contents/"):
+			workflow := "on: push\njobs: {}\n"
+			json.NewEncoder(w).Encode(map[string]string{
+				"content": base64.StdEncoding.EncodeToString([]byte(workflow)), "encoding": "base64",
+			})
+		case strings.Contains(r.URL.EscapedPath(), "/branches/"):
+			branchPath = r.URL.EscapedPath()
+			json.NewEncoder(w).Encode(map[string]any{"commit": map[string]string{"sha": "abc1234"}})
+		}
+	}))
+
+	run := WorkflowRun{ID: 1, Name: "CI", HeadBranch: "fix/a#b c", HeadSHA: "abc1234", Path: ".github/workflows/ci.yml"}
+	if _, err := c.RunOnLatest(run); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/api/v3/repos/owner/repo/branches/fix%2Fa%23b%20c"; branchPath != want {
+		t.Errorf("branch requested as %q, want %q", branchPath, want)
+	}
+}
+
+func TestListRunsForBranchMergesSubstringMatches(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 1, 1, h, 0, 0, 0, time.UTC) }
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		runs := []WorkflowRun{
+			{ID: 3, HeadBranch: "feature/x", CreatedAt: at(3)},
+			{ID: 2, HeadBranch: "feature", CreatedAt: at(2)},
+			{ID: 1, HeadBranch: "main", CreatedAt: at(1)},
+		}
+		if r.URL.Query().Get("branch") == "feature" {
+			runs = runs[1:2]
+		}
+		json.NewEncoder(w).Encode(map[string]any{"workflow_runs": runs})
+	}))
+
+	got, err := c.ListRunsForBranch("feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, r := range got {
+		ids = append(ids, r.ID)
+	}
+	if want := []int64{3, 2}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListRunsForBranch runs = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyBinding is one key of a screen. The footer hints and the ? help overlay
+// are both built from these tables so they can't drift apart.
+type keyBinding struct {
+	key  string // as shown, e.g. "enter" or "r/tab"
+	hint string // footer label; bindings without one appear only in the help
+	help string // description in the help overlay
+}
+
+var (
+	globalKeys = []keyBinding{
+		{"W", "actions tab", "Open the repository's Actions tab in browser"},
+		{"?", "help", "Show or hide this help"},
+		{"wheel", "", "Scroll the log, or move the selection in lists"},
+		{"q", "quit", "Quit"},
+	}
+
+	menuKeys = []keyBinding{
+		{"↑/↓", "navigate", "Move the selection"},
+		{"enter", "open", "Open the selected section"},
+		{"/", "branch runs", "Search runs by branch name (exact, or contained in the last 100 runs' branches)"},
+	}
+
+	runsKeys = []keyBinding{
+		{"enter", "open", "Open jobs for the selected run"},
+		{"r", "rerun-failed", "Re-run failed jobs"},
+		{"R", "rerun-all", "Re-run all jobs"},
+		{"l", "run on latest", "Run the workflow again on the latest commit of the run's branch, not the run's commit"},
+		{"x", "cancel", "Cancel the selected run (in progress or queued)"},
+		{"a", "review", "Approve or reject a deployment awaiting your review"},
+		{"d", "dispatch", "Dispatch a workflow"},
+		{"o", "browser", "Open the run in browser"},
+		{"Y", "workflow file", "Open the workflow file as of the run's commit in browser"},
+		{"tab", "refresh", "Refresh (also ctrl+r)"},
+		{"/", "", "Filter runs by name, branch, commit SHA, commit subject or actor"},
+		{"f", "status filter", "Show all, failed, in-progress or successful runs, cycling through them"},
+		{"J", "all PR jobs", "List the jobs of all runs of the PR together (PR runs only)"},
+		{"P/C", "PR/checks", "Open the PR conversation / Checks tab in browser (PR runs only)"},
+		{"L", "", "Copy a tgh --run command that opens the selected run"},
+		{"esc/b", "back", "Back"},
+	}
+
+	jobsKeys = []keyBinding{
+		{"enter", "logs", "Open logs for the selected job"},
+		{"u", "refresh job", "Refresh the selected job's status"},
+		{"f/p", "next failed/running", "Jump to the next failed / in-progress job"},
+		{"/", "filter", "Filter jobs by name; esc clears the filter"},
+		{"i", "metrics", "Toggle run metrics (queue time, parallelism)"},
+		{"E", "export", "Export the run summary as HTML and open it"},
+		{"c", "copy links", "Copy the run and job URLs to clipboard"},
+		{"A", "artifacts", "List the run's artifacts"},
+		{"L", "tgh link", "Copy a tgh --job command that opens the selected job"},
+		{"s", "sidebar", "Toggle the recent runs sidebar (terminals ≥ 140 columns)"},
+		{"o", "open", "Open job in browser"},
+		{"Y", "workflow file", "Open the workflow file as of the run's commit in browser"},
+		{"r", "rerun-failed", "Re-run failed jobs"},
+		{"R", "rerun-all", "Re-run all jobs"},
+		{"J", "rerun job", "Re-run the selected job and the jobs that depend on it (completed jobs)"},
+		{"l", "run on latest", "Run the workflow again on the latest commit of the run's branch, not the run's commit"},
+		{"x", "cancel", "Cancel the run (in progress or queued)"},
+		{"a", "review", "Approve or reject a deployment awaiting your review"},
+		{"esc/b", "back", "Back to runs"},
+	}
+
+	logsKeys = []keyBinding{
+		{"↑/↓", "scroll", "Scroll"},
+		{"PgUp/PgDn", "", "Scroll by page"},
+		{"g", "top", "Jump to top"},
+		{"G", "bottom", "Jump to bottom"},
+		{"a", "auto-scroll", "Toggle auto-scroll"},
+		{"f", "follow step", "Follow the running step's output (running jobs)"},
+		{"p", "pin step", "Pin the view at the current step's start (running jobs)"},
+		{"/", "search", "Search the log; matches are highlighted"},
+		{"n/N", "next/prev match", "Jump to the next / previous match"},
+		{"e", "next error", "Jump to the next ##[error] line, cycling through them"},
+		{"tab", "next group", "Select the next log group (shift+tab: previous)"},
+		{"space", "fold", "Fold or unfold the selected group, or the one at the top of the view (also enter)"},
+		{"Z", "", "Fold all groups, or unfold them when all are folded"},
+		{"&", "filter", "Filter log lines (show only matching lines)"},
+		{"#", "step numbers", "Toggle step numbers in the steps panel (running jobs)"},
+		{"D", "collapse", "Collapse repeated consecutive lines"},
+		{"M", "colors", "Toggle the colors printed by the job"},
+		{"t", "timestamps", "Show each line's original timestamp"},
+		{"ctrl+w", "wrap", "Wrap long lines at the window width"},
+		{"c/C", "copy", "Copy the log / with original timestamps to clipboard"},
+		{"L", "", "Copy a tgh --job command that opens this job"},
+		{"F", "error source", "Open the source of the job's first error in browser"},
+		{"E", "annotations", "List the job's error, warning and notice annotations"},
+		{"J", "rerun job", "Re-run this job and the jobs that depend on it (completed jobs)"},
+		{"S", "summary", "Show the job summary in place of the log"},
+		{"V", "compare", "Compare with the same job of an earlier run, side by side"},
+		{"H", "compact header", "Merge the status and run lines into one row"},
+		{"s", "sidebar", "Toggle the recent runs sidebar (terminals ≥ 140 columns)"},
+		{"o", "open", "Open job in browser"},
+		{"O", "open failed step", "Open the job in browser at its failed step"},
+		{"r", "refresh", "Refresh"},
+		{"R", "restart stream", "Restart the live stream from the first line (running jobs)"},
+		{"esc/b", "back", "Back to jobs"},
+	}
+
+	diffKeys = []keyBinding{
+		{"↑/↓", "scroll", "Scroll both logs"},
+		{"]/[", "next/prev difference", "Jump to the next / previous block of differences"},
+		{"V/esc", "back to log", "Back to the log"},
+	}
+
+	prKeys = []keyBinding{
+		{"enter", "open runs", "Open runs for the selected PR"},
+		{"f", "failing log", "Jump to the log of the first failing job"},
+		{"/", "filter", "Filter PRs by number, title, author or label; esc clears the filter"},
+		{"M", "mine", "Show only your PRs, or all PRs again"},
+		{"v", "approve", "Approve the selected PR"},
+		{"m", "comment", "Comment on the selected PR"},
+		{"o", "browser", "Open PR in browser"},
+		{"r/tab", "refresh", "Refresh"},
+		{"esc/b", "back", "Back to menu"},
+	}
+
+	workflowKeys = []keyBinding{
+		{"enter", "dispatch", "Dispatch the selected workflow"},
+		{"/", "filter", "Filter workflows by name or file name"},
+		{"H", "history", "Show dispatches made through tgh for this repository"},
+		{"esc/b", "back", "Back to runs"},
+	}
+
+	historyKeys = []keyBinding{
+		{"enter", "dispatch again", "Dispatch the selected entry again"},
+		{"esc/b", "back", "Back to workflows"},
+	}
+
+	annotationKeys = []keyBinding{
+		{"enter", "open source", "Open the annotated file and line in browser"},
+		{"esc/b", "back", "Back to the log"},
+	}
+
+	artifactKeys = []keyBinding{
+		{"enter", "download to ./<name>", "Download and extract the artifact into ./<name>"},
+		{"esc/b", "back", "Back to jobs"},
+	}
+)
+
+// keyHints returns footer hints for keys, looked up in bindings and then in
+// the global keys.
+func keyHints(bindings []keyBinding, keys ...string) []string {
+	hints := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if b, ok := findBinding(bindings, k); ok {
+			hints = append(hints, "<"+k+"> "+b.hint)
+		} else if b, ok := findBinding(globalKeys, k); ok {
+			hints = append(hints, "<"+k+"> "+b.hint)
+		}
+	}
+	return hints
+}
+
+func findBinding(bindings []keyBinding, key string) (keyBinding, bool) {
+	for _, b := range bindings {
+		if b.key == key {
+			return b, true
+		}
+	}
+	return keyBinding{}, false
+}
+
+// helpSection is a screen's bindings under a title in the help overlay.
+type helpSection struct {
+	title    string
+	state    viewState
+	bindings []keyBinding
+}
+
+var helpSections = []helpSection{
+	{"Runs", stateRuns, runsKeys},
+	{"Jobs", stateJobs, jobsKeys},
+	{"Logs", stateLogs, logsKeys},
+	{"Log comparison", stateLogs, diffKeys},
+	{"Pull requests", statePRs, prKeys},
+	{"Workflow dispatch", stateWorkflows, workflowKeys},
+	{"Dispatch history", stateDispatchHistory, historyKeys},
+	{"Artifacts", stateArtifacts, artifactKeys},
+	{"Annotations", stateAnnotations, annotationKeys},
+	{"Menu", stateMenu, menuKeys},
+}
+
+// openHelp shows the help overlay, starting at the current screen's keys.
+func (m *model) openHelp() {
+	m.showHelp = true
+	m.helpViewport = viewport.New(m.width, max(1, m.height-2))
+	m.helpViewport.SetContent(m.helpContent())
+}
+
+// helpContent lists the current screen's keys, the global keys and the status
+// legend, followed by the keys of every other screen.
+func (m model) helpContent() string {
+	sections := []helpSection{}
+	for _, s := range helpSections {
+		if s.state == m.state {
+			sections = append(sections, s)
+		}
+	}
+	sections = append(sections, helpSection{title: "Everywhere", bindings: globalKeys})
+	for _, s := range helpSections {
+		if s.state != m.state {
+			sections = append(sections, s)
+		}
+	}
+
+	keyWidth := 0
+	for _, s := range sections {
+		for _, b := range s.bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(b.key))
+		}
+	}
+	var sb strings.Builder
+	for i, s := range sections {
+		sb.WriteString("\n " + breadcrumbStyle.Render(s.title) + "\n")
+		for _, b := range s.bindings {
+			sb.WriteString("   " + keyStyle.Render(padRight(b.key, keyWidth)) + "  " + styleDim.Render(b.help) + "\n")
+		}
+		if i == 0 {
+			sb.WriteString("\n " + breadcrumbStyle.Render("Status icons") + "\n   " + renderLegend() + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// viewHelp renders the help overlay in place of the current view.
+func (m model) viewHelp() string {
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderAppBar("Help"),
+		m.helpViewport.View(),
+		renderFooter([]string{"<↑/↓> scroll", "<?/esc/q> close"}),
+	)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dispatchRecord is one workflow dispatch made through tgh. The history is
+// local to this machine; GitHub has no record of dispatch inputs.
+type dispatchRecord struct {
+	WorkflowID int64             `yaml:"workflow_id"`
+	Workflow   string            `yaml:"workflow"`
+	Path       string            `yaml:"path"`
+	Ref        string            `yaml:"ref"`
+	Inputs     map[string]string `yaml:"inputs,omitempty"`
+	At         time.Time         `yaml:"at"`
+	By         string            `yaml:"by,omitempty"` // GitHub login of the dispatcher
+}
+
+// workflow returns the dispatched workflow, enough to dispatch it again.
+func (r dispatchRecord) workflow() Workflow {
+	return Workflow{ID: r.WorkflowID, Name: r.Workflow, Path: r.Path}
+}
+
+// maxDispatchHistory bounds the number of dispatches kept per repository.
+const maxDispatchHistory = 50
+
+// dispatchHistoryPath returns the file holding the dispatch history of all
+// repositories, keyed by host/owner/repo.
+func dispatchHistoryPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dispatch_history.yml"), nil
+}
+
+func loadAllDispatchHistory() map[string][]dispatchRecord {
+	path, err := dispatchHistoryPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var all map[string][]dispatchRecord
+	if err := yaml.Unmarshal(data, &all); err != nil {
+		dbg("ignoring dispatch history %s: %v", path, err)
+		return nil
+	}
+	return all
+}
+
+// loadDispatchHistory returns the dispatches made for repo, most recent first.
+func loadDispatchHistory(repo string) []dispatchRecord {
+	return loadAllDispatchHistory()[repo]
+}
+
+// recordDispatch prepends rec to repo's dispatch history.
+func recordDispatch(repo string, rec dispatchRecord) error {
+	path, err := dispatchHistoryPath()
+	if err != nil {
+		return err
+	}
+	all := loadAllDispatchHistory()
+	if all == nil {
+		all = make(map[string][]dispatchRecord)
+	}
+	records := append([]dispatchRecord{rec}, all[repo]...)
+	if len(records) > maxDispatchHistory {
+		records = records[:maxDispatchHistory]
+	}
+	all[repo] = records
+	data, err := yaml.Marshal(all)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// lastDispatch returns the most recent dispatch of the workflow at path made
+// for repo, used to prefill the dispatch form.
+func lastDispatch(repo, path string) (dispatchRecord, bool) {
+	for _, rec := range loadDispatchHistory(repo) {
+		if rec.Path == path {
+			return rec, true
+		}
+	}
+	return dispatchRecord{}, false
+}
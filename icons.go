@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// iconStyle selects the characters used for status icons.
+type iconStyle string
+
+const (
+	iconsAuto    iconStyle = "auto"    // unicode unless the locale isn't UTF-8
+	iconsUnicode iconStyle = "unicode" // ● ✓ ✗ ○ ⊘ –
+	iconsASCII   iconStyle = "ascii"   // * + X o / -
+)
+
+// iconSet holds the status icons. Every icon is a single column wide so the
+// list columns line up in either set.
+type iconSet struct {
+	running, success, failure, queued, cancelled, skipped, pending string
+
+	refreshing            string
+	stepDone, stepPending string // step dots of completed jobs
+	conflict              string // pull requests that can't be merged
+}
+
+var (
+	unicodeIcons = iconSet{
+		running: "●", success: "✓", failure: "✗", queued: "○", cancelled: "⊘", skipped: "–", pending: "○",
+		refreshing: "↻", stepDone: "●", stepPending: "○", conflict: "⚠",
+	}
+	asciiIcons = iconSet{
+		running: "*", success: "+", failure: "X", queued: "o", cancelled: "/", skipped: "-", pending: "o",
+		refreshing: "~", stepDone: "*", stepPending: ".", conflict: "!",
+	}
+)
+
+// icons is the icon set in use, chosen by useIcons at startup.
+var icons = unicodeIcons
+
+// useIcons selects the icon set for style, resolving auto from the locale.
+func useIcons(style iconStyle) {
+	if style == iconsASCII || (style == iconsAuto && !localeIsUTF8()) {
+		icons = asciiIcons
+		return
+	}
+	icons = unicodeIcons
+}
+
+// localeIsUTF8 reports whether the locale environment asks for UTF-8. The
+// first of LC_ALL, LC_CTYPE and LANG that is set wins, as in setlocale. An
+// unset locale is treated as UTF-8 since that is what modern terminals use.
+func localeIsUTF8() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// jsonKinds are the lists tgh --json prints.
+var jsonKinds = []string{"runs", "jobs", "prs"}
+
+// printJSON is tgh --json: it prints the latest runs, the jobs of runID or
+// the open pull requests as JSON on stdout and returns the exit code.
+func printJSON(c *GitHubClient, kind string, runID int64) int {
+	var v any
+	var err error
+	switch kind {
+	case "runs":
+		v, err = c.ListRuns()
+	case "jobs":
+		v, err = c.ListJobs(runID)
+	case "prs":
+		v, err = c.ListPullRequests()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
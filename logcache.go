@@ -0,0 +1,76 @@
+package main
+
+// defaultLogCacheMB is the log cache budget when log_cache_mb is unset.
+const defaultLogCacheMB = 64
+
+// logCache keeps the logs of completed jobs, keyed by job ID, so reopening a
+// job or one whose failed log was prefetched is instant. It is bounded by the
+// total size of the logs; the least recently used entries are evicted first.
+// Logs of running jobs are never cached since they are still growing.
+type logCache struct {
+	maxBytes int
+	size     int
+	entries  map[int64]string
+	order    []int64 // least recently used first
+}
+
+// newLogCache returns a cache holding up to maxBytes of logs. A budget of zero
+// or less disables caching.
+func newLogCache(maxBytes int) *logCache {
+	return &logCache{maxBytes: maxBytes, entries: make(map[int64]string)}
+}
+
+// get returns a job's cached log and marks it as recently used.
+func (c *logCache) get(jobID int64) (string, bool) {
+	content, ok := c.entries[jobID]
+	if ok {
+		c.touch(jobID)
+	}
+	return content, ok
+}
+
+// has reports whether a job's log is cached without affecting eviction order.
+func (c *logCache) has(jobID int64) bool {
+	_, ok := c.entries[jobID]
+	return ok
+}
+
+// put stores a job's log, evicting least recently used logs until the cache
+// fits its budget. A log larger than the whole budget is not cached.
+func (c *logCache) put(jobID int64, content string) {
+	c.remove(jobID)
+	if len(content) > c.maxBytes {
+		return
+	}
+	c.entries[jobID] = content
+	c.order = append(c.order, jobID)
+	c.size += len(content)
+	for c.size > c.maxBytes && len(c.order) > 0 {
+		c.remove(c.order[0])
+	}
+}
+
+// remove drops a job's log, if cached.
+func (c *logCache) remove(jobID int64) {
+	content, ok := c.entries[jobID]
+	if !ok {
+		return
+	}
+	delete(c.entries, jobID)
+	c.size -= len(content)
+	for i, id := range c.order {
+		if id == jobID {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *logCache) touch(jobID int64) {
+	for i, id := range c.order {
+		if id == jobID {
+			c.order = append(append(c.order[:i], c.order[i+1:]...), jobID)
+			return
+		}
+	}
+}
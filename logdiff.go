@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Comparing a job's log with the same job of an earlier run shows what is
+// different about a failure. Lines are compared with digits collapsed, so
+// timings, counters and temporary names that change on every run don't drown
+// out the differences that matter.
+
+// maxDiffEdits bounds the diff search. Logs that differ in more lines are
+// shown as one changed block past their common start and end.
+const maxDiffEdits = 2000
+
+// logDiffMinWidth is the narrowest terminal that shows the logs side by side;
+// narrower ones get a unified diff.
+const logDiffMinWidth = 100
+
+var diffDigits = regexp.MustCompile(`[0-9]+`)
+
+// diffRow pairs a line of the previous log (left) with one of the current log
+// (right). A side is missing when the other log has extra lines there.
+type diffRow struct {
+	left, right       string
+	hasLeft, hasRight bool
+	changed           bool
+}
+
+// diffKey is the form lines are compared in.
+func diffKey(line string) string {
+	return diffDigits.ReplaceAllString(stripANSI(line), "0")
+}
+
+// diffLines aligns two logs line by line.
+func diffLines(prev, cur []string) []diffRow {
+	a := make([]string, len(prev))
+	for i, l := range prev {
+		a[i] = diffKey(l)
+	}
+	b := make([]string, len(cur))
+	for i, l := range cur {
+		b[i] = diffKey(l)
+	}
+
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	var rows []diffRow
+	same := func(i, j int) {
+		rows = append(rows, diffRow{left: prev[i], right: cur[j], hasLeft: true, hasRight: true})
+	}
+	for i := 0; i < pre; i++ {
+		same(i, i)
+	}
+
+	// Removed and added lines between two common ones are paired up.
+	var dels, adds []int
+	flush := func() {
+		for k := 0; k < max(len(dels), len(adds)); k++ {
+			r := diffRow{changed: true}
+			if k < len(dels) {
+				r.left, r.hasLeft = prev[dels[k]], true
+			}
+			if k < len(adds) {
+				r.right, r.hasRight = cur[adds[k]], true
+			}
+			rows = append(rows, r)
+		}
+		dels, adds = dels[:0], adds[:0]
+	}
+
+	i, j := pre, pre
+	ops, ok := diffOps(a[pre:len(a)-suf], b[pre:len(b)-suf])
+	if !ok {
+		ops = append([]byte(strings.Repeat("-", len(a)-suf-pre)), strings.Repeat("+", len(b)-suf-pre)...)
+	}
+	for _, op := range ops {
+		switch op {
+		case '=':
+			flush()
+			same(i, j)
+			i++
+			j++
+		case '-':
+			dels = append(dels, i)
+			i++
+		case '+':
+			adds = append(adds, j)
+			j++
+		}
+	}
+	flush()
+
+	for k := 0; k < suf; k++ {
+		same(len(prev)-suf+k, len(cur)-suf+k)
+	}
+	return rows
+}
+
+// diffOps computes a shortest edit script from a to b with Myers' algorithm:
+// '=' keeps a line, '-' drops one of a, '+' adds one of b. It gives up when
+// more than maxDiffEdits edits are needed.
+func diffOps(a, b []string) ([]byte, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDiffEdits)
+	off := limit + 1
+	v := make([]int, 2*off+1)
+	var trace [][]int // trace[d][k+d] is the furthest x on diagonal k after d edits
+
+	found := -1
+	for d := 0; d <= limit && found < 0; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				found = d
+				break
+			}
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+	if found < 0 {
+		return nil, false
+	}
+
+	var ops []byte
+	x, y := n, m
+	for d := found; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, '=')
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, '+')
+			y--
+		} else {
+			ops = append(ops, '-')
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, '=')
+		x--
+		y--
+	}
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	return ops, true
+}
+
+// renderLogDiff renders aligned rows side by side (previous log on the left)
+// or, on narrow terminals, as a unified diff. It returns the rendered lines
+// and the line index where each block of differences starts.
+func renderLogDiff(rows []diffRow, width int, redact redactConfig) (string, []int) {
+	cell := func(s string) string {
+		return sanitizeLogLine(stripANSI(redact.apply(s)))
+	}
+	var lines []string
+	var hunks []int
+	for i, r := range rows {
+		if r.changed && (i == 0 || !rows[i-1].changed) {
+			hunks = append(hunks, len(lines))
+		}
+		if width >= logDiffMinWidth {
+			w := (width - 3) / 2
+			left, right := padRight(truncate(cell(r.left), w), w), truncate(cell(r.right), w)
+			if r.changed {
+				left, right = styleError.Render(left), statusSuccess.Render(right)
+			}
+			lines = append(lines, left+styleDim.Render(" │ ")+right)
+			continue
+		}
+		switch {
+		case !r.changed:
+			lines = append(lines, "  "+truncate(cell(r.right), width-2))
+		default:
+			if r.hasLeft {
+				lines = append(lines, styleError.Render("- "+truncate(cell(r.left), width-2)))
+			}
+			if r.hasRight {
+				lines = append(lines, statusSuccess.Render("+ "+truncate(cell(r.right), width-2)))
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), hunks
+}
+
+// logDiffHeader labels the two sides of the comparison.
+func logDiffHeader(prev WorkflowRun, prevJob Job, width int, f timeFormat) string {
+	sha := prev.HeadSHA[:min(7, len(prev.HeadSHA))]
+	prevLabel := fmt.Sprintf("previous: %s (%s, %s)", sha, prevJob.Conclusion, relativeTime(prev.CreatedAt, f))
+	if width < logDiffMinWidth {
+		return colHeaderStyle.Render(" " + styleError.Render("- "+prevLabel) + "  " + statusSuccess.Render("+ this run"))
+	}
+	w := (width - 3) / 2
+	return colHeaderStyle.Render(padRight(prevLabel, w) + " │ this run")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// applyOps rebuilds b from a and an edit script, checking that the script
+// accounts for every line of both.
+func applyOps(t *testing.T, a, b []string, ops []byte) []string {
+	t.Helper()
+	var out []string
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case '=':
+			if a[i] != b[j] {
+				t.Fatalf("'=' pairs %q with %q", a[i], b[j])
+			}
+			out = append(out, a[i])
+			i++
+			j++
+		case '-':
+			i++
+		case '+':
+			out = append(out, b[j])
+			j++
+		}
+	}
+	if i != len(a) || j != len(b) {
+		t.Fatalf("script covers %d of %d and %d of %d lines", i, len(a), j, len(b))
+	}
+	return out
+}
+
+func TestDiffOps(t *testing.T) {
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, " ")
+	}
+	tests := []struct {
+		name  string
+		a, b  string
+		edits int // length of the shortest edit script
+	}{
+		{"both empty", "", "", 0},
+		{"all added", "", "x y z", 3},
+		{"all removed", "x y z", "", 3},
+		{"equal", "a b c", "a b c", 0},
+		{"disjoint", "a b c", "x y", 5},
+		{"one changed", "a b c", "a x c", 2},
+		{"insert in the middle", "a b c", "a b x c", 1},
+		{"moved line", "a b c d", "b c d a", 2},
+		{"classic", "a b c a b b a", "c b a b a c", 5},
+	}
+	for _, tt := range tests {
+		a, b := split(tt.a), split(tt.b)
+		ops, ok := diffOps(a, b)
+		if !ok {
+			t.Fatalf("%s: diffOps gave up", tt.name)
+		}
+		if got := applyOps(t, a, b, ops); !slices.Equal(got, b) {
+			t.Errorf("%s: script rebuilds %q, want %q", tt.name, got, b)
+		}
+		edits := len(ops) - strings.Count(string(ops), "=")
+		if edits != tt.edits {
+			t.Errorf("%s: %d edits (%s), want %d", tt.name, edits, ops, tt.edits)
+		}
+	}
+}
+
+func TestDiffOpsGivesUpPastTheLimit(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf("%s%d", prefix, i)
+		}
+		return out
+	}
+	// Disjoint logs need one edit per line.
+	if _, ok := diffOps(lines("a", maxDiffEdits/2), lines("b", maxDiffEdits/2)); !ok {
+		t.Error("diffOps gave up at exactly maxDiffEdits edits")
+	}
+	if _, ok := diffOps(lines("a", maxDiffEdits/2), lines("b", maxDiffEdits/2+1)); ok {
+		t.Error("diffOps succeeded past maxDiffEdits edits")
+	}
+	// Long but similar logs stay within the limit.
+	a := lines("x", 3*maxDiffEdits)
+	b := slices.Clone(a)
+	b[maxDiffEdits] = "changed"
+	if ops, ok := diffOps(a, b); !ok || len(ops) != len(a)+1 {
+		t.Errorf("diffOps on similar long logs = %d ops, %v; want %d ops", len(ops), ok, len(a)+1)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Steps and actions wrap their output in ##[group] / ##[endgroup] markers.
+// Groups can be folded to skim a long log: a folded group shows only its
+// header. Groups are identified by their position in the log, so folds
+// survive a refresh of the same job's log. GitHub doesn't nest groups.
+
+// foldGroups hides the lines of the folded groups up to and including their
+// ##[endgroup], noting the number of hidden lines on the group's header. src
+// holds each line's logRaw index and is reduced alongside.
+func foldGroups(lines []string, src []int, folded map[int]bool) ([]string, []int) {
+	out := make([]string, 0, len(lines))
+	outSrc := make([]int, 0, len(src))
+	group := -1
+	for i := 0; i < len(lines); i++ {
+		outSrc = append(outSrc, src[i])
+		if !strings.HasPrefix(lines[i], "##[group]") {
+			out = append(out, lines[i])
+			continue
+		}
+		group++
+		if !folded[group] {
+			out = append(out, lines[i])
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "##[endgroup]") && !strings.HasPrefix(lines[end], "##[group]") {
+			end++
+		}
+		hidden := end - i - 1
+		if end < len(lines) && strings.HasPrefix(lines[end], "##[endgroup]") {
+			end++
+		}
+		note := fmt.Sprintf("%d lines folded", hidden)
+		if hidden == 1 {
+			note = "1 line folded"
+		}
+		out = append(out, lines[i]+"  … "+note)
+		i = end - 1
+	}
+	return out, outSrc
+}
+
+// groupHeaders returns the display line of each group's header, indexed by
+// group.
+func groupHeaders(display string) []int {
+	var headers []int
+	for i, line := range strings.Split(display, "\n") {
+		if strings.HasPrefix(line, "##[group]") {
+			headers = append(headers, i)
+		}
+	}
+	return headers
+}
+
+// highlightGroupCursor marks the header row of the group cursor in the
+// rendered log.
+func (m model) highlightGroupCursor(rendered string) string {
+	if m.groupCursor < 0 || m.groupCursor >= len(m.groupLines) {
+		return rendered
+	}
+	rows := strings.Split(rendered, "\n")
+	row := m.logRow(m.groupLines[m.groupCursor])
+	if row >= len(rows) {
+		return rendered
+	}
+	if noColor {
+		// The highlight doesn't show; point at the header instead.
+		rows[row] = strings.Replace(rows[row], "▶", "»", 1)
+	} else {
+		rows[row] = styleGroupCursor.Render(stripANSI(rows[row]))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// currentGroup returns the group at the cursor or, without one, the group
+// whose header is at or above the top of the viewport; -1 when there is none.
+func (m model) currentGroup() int {
+	if m.groupCursor >= 0 && m.groupCursor < len(m.groupLines) {
+		return m.groupCursor
+	}
+	top := m.logLineAt(m.logViewport.YOffset)
+	group := -1
+	for g, line := range m.groupLines {
+		if line > top {
+			break
+		}
+		group = g
+	}
+	return group
+}
+
+// moveGroupCursor moves the group cursor delta headers forward (or back when
+// negative) and scrolls its header into view. The first move starts from the
+// group at the top of the viewport.
+func (m *model) moveGroupCursor(delta int) {
+	if !m.groupsFoldable() {
+		return
+	}
+	n := len(m.groupLines)
+	if m.groupCursor < 0 || m.groupCursor >= n {
+		m.groupCursor = max(0, m.currentGroup())
+	} else {
+		m.groupCursor = ((m.groupCursor+delta)%n + n) % n
+	}
+	m.followStep = false
+	m.logPinned = false
+	m.autoScroll = false
+	m.renderLogContent()
+	row := m.logRow(m.groupLines[m.groupCursor])
+	if row < m.logViewport.YOffset || row >= m.logViewport.YOffset+m.logViewport.Height {
+		m.logViewport.SetYOffset(max(0, row-m.logViewport.Height/3))
+	}
+}
+
+// toggleGroup folds or unfolds the current group, keeping its header in place.
+func (m *model) toggleGroup() {
+	if !m.groupsFoldable() {
+		return
+	}
+	group := m.currentGroup()
+	if group < 0 {
+		m.statusMsg = "No group at this position"
+		return
+	}
+	m.groupCursor = group
+	m.foldedGroups[group] = !m.foldedGroups[group]
+	m.refoldKeepingRow(group)
+}
+
+// toggleAllGroups folds every group, or unfolds them all when all are folded.
+func (m *model) toggleAllGroups() {
+	if !m.groupsFoldable() {
+		return
+	}
+	fold := false
+	for g := range m.groupLines {
+		if !m.foldedGroups[g] {
+			fold = true
+			break
+		}
+	}
+	for g := range m.groupLines {
+		m.foldedGroups[g] = fold
+	}
+	m.refoldKeepingRow(max(0, m.currentGroup()))
+}
+
+// refoldKeepingRow re-renders the log after folds changed, keeping the header
+// of group at the same position on screen.
+func (m *model) refoldKeepingRow(group int) {
+	screenRow := m.logRow(m.groupLines[group]) - m.logViewport.YOffset
+	m.followStep = false
+	m.logPinned = false
+	m.autoScroll = false
+	m.renderLogContent()
+	m.logViewport.SetYOffset(max(0, m.logRow(m.groupLines[group])-screenRow))
+}
+
+// groupsFoldable reports whether the log's groups can be folded; the line
+// filter shows matching lines regardless of their group.
+func (m *model) groupsFoldable() bool {
+	if m.logFilter != "" {
+		m.statusMsg = "Clear the filter to fold groups"
+		return false
+	}
+	if len(m.groupLines) == 0 {
+		m.statusMsg = "No groups in this log"
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// The log API prefixes every line with an RFC 3339 timestamp, which
+// processLogLines strips for reading. With t the timestamps are shown again,
+// as a dimmed column left of the log. Only fetched logs keep them: the live
+// stream of a running job arrives without.
+
+// logStampLayout formats the timestamp column; logStampWidth is the column's
+// width including the gap to the log text.
+const (
+	logStampLayout = "15:04:05.000"
+	logStampWidth  = len(logStampLayout) + 1
+)
+
+// logStamps returns the rendered timestamp column for the displayed lines
+// whose logRaw indices are src, or nil when timestamps are off or unknown.
+func (m model) logStamps(src []int) []string {
+	if !m.showTimestamps || m.logTimestamped == "" {
+		return nil
+	}
+	raw := strings.Split(m.logTimestamped, "\n")
+	blank := strings.Repeat(" ", logStampWidth)
+	stamps := make([]string, len(src))
+	for i, idx := range src {
+		stamps[i] = blank
+		if idx >= len(raw) {
+			continue
+		}
+		prefix, _, ok := strings.Cut(raw[idx], " ")
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			stamps[i] = styleDim.Render(t.Local().Format(logStampLayout)) + " "
+		}
+	}
+	return stamps
+}
+
+// prefixLogRows puts each line's stamp in front of the first row it renders
+// to, indenting the rows wrapped below it; rows is nil when not wrapping.
+func prefixLogRows(rendered string, rows []int, stamps []string) string {
+	out := strings.Split(rendered, "\n")
+	stamped := make([]bool, len(out))
+	for i, stamp := range stamps {
+		row := i
+		if rows != nil {
+			if i >= len(rows) {
+				break
+			}
+			row = rows[i]
+		}
+		if row < len(out) {
+			out[row] = stamp + out[row]
+			stamped[row] = true
+		}
+	}
+	blank := strings.Repeat(" ", logStampWidth)
+	for r := range out {
+		if !stamped[r] {
+			out[r] = blank + out[r]
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,20 +25,49 @@ import (
 type viewState int
 
 const (
-	stateMenu          viewState = iota // main menu
-	stateRuns                           // list of workflow runs
-	stateJobs                           // jobs for a selected run
-	stateLogs                           // live log viewer for a selected job
-	statePRs                            // list of open pull requests
-	stateWorkflows                      // workflow dispatch picker
-	stateDispatchForm                   // form to fill inputs before dispatching
+	stateMenu            viewState = iota // main menu
+	stateRuns                             // list of workflow runs
+	stateJobs                             // jobs for a selected run
+	stateLogs                             // live log viewer for a selected job
+	statePRs                              // list of open pull requests
+	stateWorkflows                        // workflow dispatch picker
+	stateDispatchForm                     // form to fill inputs before dispatching
+	stateDispatchHistory                  // dispatches previously made through tgh
+	stateArtifacts                        // artifacts of the selected run
+	stateAnnotations                      // errors and warnings of the selected job
 )
 
+// confirmPrompt is a yes/no question shown as an overlay. onYes runs when the
+// user confirms; declining just dismisses the prompt.
+type confirmPrompt struct {
+	message string
+	onYes   tea.Cmd
+}
+
 // model is the root Bubble Tea model.
 type model struct {
 	state         viewState
 	width, height int
 	client        *GitHubClient
+	config        Config
+	poll          pollIntervals
+
+	// polling backs off while requests fail; restarting the poll loops bumps
+	// pollGen so ticks scheduled before are dropped
+	pollFailures int // consecutive failed requests while polling
+	pollGen      int
+
+	// confirmation, deployment review and PR comment overlays shown on top of
+	// the current view; nil when hidden
+	confirm   *confirmPrompt
+	review    *reviewPrompt
+	prComment *prCommentPrompt
+
+	// session being restored at startup; cleared once replayed or on any key
+	restore *session
+
+	// --run/--job target, opened once fetched
+	deepLink *session
 
 	// stateMenu
 	menuIndex int
@@ -40,31 +75,79 @@ type model struct {
 	// stateRuns
 	runsList    list.Model
 	runsPolling bool
+	prChecks    map[string]ChecksRollup // checks rollup by head SHA, for the PR list and PR-scoped runs
+	runProgress map[int64]jobProgress
+	progressIn  map[int64]bool // runs with a job-count fetch outstanding
 
 	// stateJobs
 	selectedRun      WorkflowRun
 	jobsList         list.Model
 	jobsPolling      bool
 	jobsPollStartIDs map[int64]bool
+	prJobsRuns       []WorkflowRun  // runs whose jobs are listed together; nil for a single run
+	showRunMetrics   bool           // queue time / parallelism panel above the jobs list
+	runTiming        *RunTiming     // timing for runTimingRunID; nil when unavailable
+	runTimingRunID   int64          // run the timing was fetched for (0 = not fetched)
+	runSchedule      *scheduleMatch // cron entry behind runScheduleRunID, a scheduled run
+	runScheduleRunID int64
+
+	// run sidebar (jobs and logs views on wide terminals)
+	sidebarHidden bool // user toggled the sidebar off
 
 	// stateLogs
-	selectedJob   Job
-	logViewport   viewport.Model
-	logContent    string // rendered content with styling
-	logRaw        string // raw log content (unrendered)
-	logLoaded     bool
-	autoScroll    bool
-	lastLogLength int // track log size to detect incremental updates
+	selectedJob    Job
+	logViewport    viewport.Model
+	logContent     string // rendered content with styling
+	logRaw         string // raw log content (unrendered)
+	logTimestamped string // original log text with timestamps (completed jobs only)
+	logLoaded      bool
+	autoScroll     bool
+	followStep     bool   // keep the running step's log group in view while streaming
+	collapseDupes  bool   // show runs of identical consecutive lines once with a (×N) suffix
+	stepNumbers    bool   // prefix steps with their number in the steps panel and status line
+	logPinned      bool   // viewport anchored at logAnchor while output streams below
+	compactHeader  bool   // logs view merges the status and run lines into one row
+	plainLogs      bool   // strip the colors jobs print from the log view
+	wrapEnabled    bool   // hard-wrap long log lines at the viewport width
+	showTimestamps bool   // show each line's original timestamp left of it
+	logRows        []int  // viewport row of each displayed log line while wrapping
+	logAnchor      int    // line of the ##[group] boundary the viewport is pinned to
+	logAnchorName  string // group title at logAnchor, shown in the status line
+	lastLogLength  int    // track log size to detect incremental updates
+
+	// job summary panel, shown in place of the log
+	showSummary     bool
+	summaryJobID    int64 // job the summary below belongs to
+	summaryLoaded   bool
+	summaryText     string // markdown; empty when the job has no summary
+	summaryViewport viewport.Model
+
+	// comparison with the same job of an earlier run, shown in place of the log
+	showDiff     bool
+	diffJobID    int64 // job the comparison below belongs to
+	diffLoaded   bool
+	diffPrevRun  WorkflowRun
+	diffPrevJob  Job
+	diffRows     []diffRow
+	diffHunks    []int // rendered line of each block of differences
+	diffHunkIdx  int
+	diffViewport viewport.Model
 
 	// live streaming (running jobs)
+	liveGen            int // bumped by resetLiveState so stale live messages are dropped
 	liveStreaming      bool
+	liveInFlight       bool // a live log fetch or strategy probe is outstanding
 	liveChangeID       int
 	liveLogs           string
 	liveFailedAttempts int
 
 	// blob range polling (fallback for running jobs)
-	logBlobURL    string
-	logBlobOffset int64
+	logBlobURL     string
+	logBlobOffset  int64
+	logBlobGzip    bool
+	logBlobPartial string    // text after the blob's last newline so far
+	logWaits       int       // consecutive polls that found no logs yet (just-started job)
+	logWaitUntil   time.Time // no live fetch before this time while waiting for logs
 
 	// GHES per-step log fetching
 	pipelineInfo    *pipelineServiceInfo
@@ -73,69 +156,234 @@ type model struct {
 	// log filter
 	logFilter     string
 	logFilterMode bool
+	logFiltered   bool // viewport currently shows filtered content
+	preFilterYOff int  // scroll position to restore when the filter is cleared
+	preFilterAuto bool // auto-scroll state to restore when the filter is cleared
+
+	// log search: matches are highlighted in the full log and visited with n/N
+	logSearch     string
+	logSearchMode bool
+	searchMatches []int // display line indices containing logSearch
+	searchIdx     int   // current entry of searchMatches
+	searchOrigin  int   // scroll position when the search was started
+	errorIdx      int   // ##[error] line last jumped to with e, -1 before the first jump
+
+	// folded log groups, by group index so folds survive a log refresh
+	foldedGroups map[int]bool
+	groupCursor  int   // group header selected with tab, -1 for none
+	groupLines   []int // display line of each group header
+
+	// background log prefetch for failed jobs
+	logCache       *logCache      // completed job logs, also opened ones
+	logPrefetching map[int64]bool // job IDs with a prefetch in flight
 
 	// statePRs
 	prsList    list.Model
 	selectedPR *PullRequest // non-nil when viewing runs for a specific PR
 
+	// pull requests as loaded; prsMine (toggled with M) lists only those
+	// opened by login, the authenticated user, looked up on first use
+	loadedPRs []PullRequest
+	prsMine   bool
+	login     string
+
+	prMergeable map[int]PRStatus // mergeability of PRs by number
+
+	// runs as loaded, before runStatusFilter (cycled with f) narrows the list
+	loadedRuns      []WorkflowRun
+	runStatusFilter runStatusFilter
+
+	// branch search started with / on the menu; branchFilter is set while the
+	// runs list shows the runs of the searched branch
+	branchSearch     string
+	branchSearchMode bool
+	branchFilter     string
+
 	// stateWorkflows
-	workflowsList list.Model
-	defaultBranch string
+	workflowsList      list.Model
+	historyList        list.Model          // stateDispatchHistory
+	artifactsList      list.Model          // stateArtifacts
+	artifactsRunID     int64               // run the artifacts list belongs to
+	annotationsList    list.Model          // stateAnnotations
+	annotationsJobID   int64               // job the annotations list belongs to
+	pendingDeploys     []PendingDeployment // deployments of pendingRunID awaiting review
+	pendingRunID       int64
+	defaultBranch      string
+	dispatchRefMissing bool // configured dispatch ref is not a branch or tag of the repo
 
 	// stateDispatchForm
 	selectedWorkflow Workflow
 	formFields       []formField
 	formActiveField  int
-	formButton       int      // 0=field focused, 1=Cancel focused, 2=Build focused
-	refBranches      []string // all branch names (from API)
-	refTags          []string // all tag names (from API)
-	refSection       int      // 0=input, 1=branches, 2=tags
-	refBranchIdx     int      // selected index in filtered branch list
-	refTagIdx        int      // selected index in filtered tag list
+	formButton       int             // 0=field focused, 1=Cancel focused, 2=Build focused
+	dispatchPreview  bool            // showing the confirmation panel before dispatching
+	dispatchInFlight bool            // a dispatch request has been sent but not answered
+	refBranches      []string        // all branch names (from API)
+	refTags          []string        // all tag names (from API)
+	refSection       int             // 0=input, 1=branches, 2=tags
+	refBranchIdx     int             // selected row in branchRows (filtered, possibly grouped)
+	refCollapsed     map[string]bool // collapsed branch prefix groups (ref_groups)
+	refTagIdx        int             // selected index in filtered tag list
+
+	// variables per environment for the environment input hint; nil while
+	// loading or when the token can't read them
+	envVars map[string][]EnvVariable
 
 	// shared
 	spinner        spinner.Model
 	loading        bool
 	statusMsg      string
+	statusSeq      int  // bumped whenever statusMsg changes; stale clear ticks are ignored
+	statusExpires  bool // statusMsg is a confirmation set by this update
+	showHelp       bool // ? overlay listing every key binding
+	helpViewport   viewport.Model
 	err            error
 	lastJobsForRun map[int64][]Job
+	completingRuns map[int64][]Job // runs whose jobs all finished, by ID, until the run reports completed
 }
 
 // ─── List item types ──────────────────────────────────────────────────────────
 
-type runItem struct{ run WorkflowRun }
+type runItem struct {
+	run      WorkflowRun
+	progress jobProgress // completed/total jobs, only known for in-progress runs
+}
 
-func (r runItem) FilterValue() string { return r.run.Name + " " + r.run.HeadBranch }
+// jobProgress counts a run's completed jobs out of all its jobs.
+type jobProgress struct{ done, total int }
 
-type jobItem struct{ job Job }
+// label renders the progress as "3/8", or "" when unknown.
+func (p jobProgress) label() string {
+	if p.total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", p.done, p.total)
+}
+
+func (r runItem) FilterValue() string {
+	return r.run.Name + " " + r.run.HeadBranch + " " + r.run.HeadSHA + " " + firstLine(r.run.HeadCommit.Message) + " " + r.run.Actor.Login
+}
+
+type jobItem struct {
+	job        Job
+	workflow   string // workflow name, set when listing jobs across several runs
+	refreshing bool   // a single-job status fetch is outstanding
+}
 
 func (j jobItem) FilterValue() string { return j.job.Name }
 
-type prItem struct{ pr PullRequest }
+type prItem struct {
+	pr     PullRequest
+	checks *ChecksRollup // nil until the PR's checks are fetched
+}
 
-func (p prItem) FilterValue() string { return fmt.Sprintf("#%d %s", p.pr.Number, p.pr.Title) }
+// FilterValue matches on the PR's number, title, author and labels.
+func (p prItem) FilterValue() string {
+	v := fmt.Sprintf("#%d %s @%s", p.pr.Number, p.pr.Title, p.pr.User.Login)
+	for _, l := range p.pr.Labels {
+		v += " " + l.Name
+	}
+	return v
+}
 
 type workflowItem struct{ wf Workflow }
 
-func (w workflowItem) FilterValue() string { return w.wf.Name }
+// FilterValue matches on the workflow's name and file name.
+func (w workflowItem) FilterValue() string { return w.wf.Name + " " + path.Base(w.wf.Path) }
+
+type artifactItem struct{ a Artifact }
+
+func (a artifactItem) FilterValue() string { return a.a.Name }
+
+type historyItem struct{ rec dispatchRecord }
+
+func (h historyItem) FilterValue() string { return h.rec.Workflow }
+
+// validNumber reports whether s is accepted by a number input; GitHub fails
+// the run right away when it can't coerce the value. Empty is not sent.
+func validNumber(s string) bool {
+	if s == "" {
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// numberRunes reports whether runes can be part of a number, so number
+// inputs ignore other typing.
+func numberRunes(runes []rune) bool {
+	for _, r := range runes {
+		if !strings.ContainsRune("0123456789.-+eE", r) {
+			return false
+		}
+	}
+	return true
+}
 
 // formField holds one field in the workflow dispatch form.
 type formField struct {
-	label       string
-	description string
-	fieldType   string   // "ref", "string", "boolean", "choice", "environment"
-	options     []string // for "choice" type
-	required    bool
-	optionIdx   int // current selected index for choice/boolean cycling
-	input       textinput.Model
+	label        string
+	description  string
+	fieldType    string   // "ref", "string", "number", "boolean", "choice", "environment"
+	options      []string // for "choice" type
+	required     bool
+	defaultValue string // initial value derived from the workflow YAML default
+	optionIdx    int    // current selected index for choice/boolean cycling
+	input        textinput.Model
+	area         *textarea.Model // multi-line editor replacing input once expanded (string fields)
+}
+
+// value returns the field's current value, from the multi-line editor when
+// the field is expanded.
+func (f formField) value() string {
+	if f.area != nil {
+		return f.area.Value()
+	}
+	return f.input.Value()
+}
+
+// Bounds of an expanded field's editor, which grows with its content.
+const (
+	minAreaHeight = 3
+	maxAreaHeight = 10
+)
+
+// expand replaces the field's single-line input with a multi-line editor
+// holding value.
+func (f *formField) expand(value string) {
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.SetWidth(60)
+	ta.SetValue(value)
+	f.area = &ta
+	f.fitArea()
+}
+
+// fitArea sizes the editor to its lines, plus one to type into.
+func (f *formField) fitArea() {
+	f.area.SetHeight(max(minAreaHeight, min(f.area.LineCount()+1, maxAreaHeight)))
+}
+
+// collapse returns an expanded field to its single-line input. Values with
+// line breaks can't be shown there, so they stay expanded.
+func (f *formField) collapse() bool {
+	if strings.Contains(f.area.Value(), "\n") {
+		return false
+	}
+	f.input.SetValue(f.area.Value())
+	f.area = nil
+	return true
 }
 
 // ─── Custom delegates (k9s-style single-line table rows) ─────────────────────
 
-type runDelegate struct{ width int }
+type runDelegate struct {
+	width      int
+	timeFormat timeFormat
+}
 
 func (d runDelegate) Height() int                             { return 1 }
-func (d runDelegate) Spacing() int                           { return 0 }
+func (d runDelegate) Spacing() int                            { return 0 }
 func (d runDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d runDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
 	ri, ok := item.(runItem)
@@ -144,52 +392,76 @@ func (d runDelegate) Render(w io.Writer, m list.Model, index int, item list.Item
 	}
 	selected := index == m.Index()
 	if selected {
-		row := formatRunRowPlain(ri.run, d.width)
+		row := formatRunRowPlain(ri.run, ri.progress, d.width, d.timeFormat)
 		visWidth := lipgloss.Width(row)
 		if visWidth < d.width {
 			row = row + strings.Repeat(" ", d.width-visWidth)
 		}
 		style := lipgloss.NewStyle().
-			Background(lipgloss.Color("63")).
-			Foreground(lipgloss.Color("15")).
+			Background(colorSelected).
+			Foreground(colorWhite).
 			Bold(true)
 		fmt.Fprint(w, style.Render(row))
 	} else {
-		fmt.Fprint(w, normalItemStyle.Render(formatRunRow(ri.run, d.width, false)))
+		fmt.Fprint(w, normalItemStyle.Render(formatRunRow(ri.run, ri.progress, d.width, false, d.timeFormat)))
 	}
 }
 
-type jobDelegate struct{ width int }
+// jobDelegate renders job rows. spinner is the current spinner frame, drawn
+// in place of the status icon on rows with a refresh outstanding.
+type jobDelegate struct {
+	width   int
+	spinner string
+}
 
 func (d jobDelegate) Height() int                             { return 1 }
-func (d jobDelegate) Spacing() int                           { return 0 }
+func (d jobDelegate) Spacing() int                            { return 0 }
 func (d jobDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d jobDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
 	ji, ok := item.(jobItem)
 	if !ok {
 		return
 	}
+	if ji.workflow != "" {
+		ji.job.Name = ji.workflow + " › " + ji.job.Name
+	}
 	selected := index == m.Index()
 	if selected {
-		row := formatJobRowPlain(ji.job, d.width)
+		row := formatJobRowPlain(ji.job, d.width, d.refreshIcon(ji))
 		visWidth := lipgloss.Width(row)
 		if visWidth < d.width {
 			row = row + strings.Repeat(" ", d.width-visWidth)
 		}
 		style := lipgloss.NewStyle().
-			Background(lipgloss.Color("63")).
-			Foreground(lipgloss.Color("15")).
+			Background(colorSelected).
+			Foreground(colorWhite).
 			Bold(true)
 		fmt.Fprint(w, style.Render(row))
 	} else {
-		fmt.Fprint(w, normalItemStyle.Render(formatJobRow(ji.job, d.width, false)))
+		fmt.Fprint(w, normalItemStyle.Render(formatJobRow(ji.job, d.width, false, d.refreshIcon(ji))))
 	}
 }
 
-type prDelegate struct{ width int }
+// refreshIcon is the icon a refreshing row shows, or "" when the row isn't
+// refreshing.
+func (d jobDelegate) refreshIcon(ji jobItem) string {
+	switch {
+	case !ji.refreshing:
+		return ""
+	case d.spinner != "":
+		return d.spinner
+	default:
+		return icons.refreshing
+	}
+}
+
+type prDelegate struct {
+	width      int
+	timeFormat timeFormat
+}
 
 func (d prDelegate) Height() int                             { return 1 }
-func (d prDelegate) Spacing() int                           { return 0 }
+func (d prDelegate) Spacing() int                            { return 0 }
 func (d prDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d prDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
 	pi, ok := item.(prItem)
@@ -198,25 +470,25 @@ func (d prDelegate) Render(w io.Writer, m list.Model, index int, item list.Item)
 	}
 	selected := index == m.Index()
 	if selected {
-		row := formatPRRowPlain(pi.pr, d.width)
+		row := formatPRRowPlain(pi.pr, pi.checks, d.width, d.timeFormat)
 		visWidth := lipgloss.Width(row)
 		if visWidth < d.width {
 			row = row + strings.Repeat(" ", d.width-visWidth)
 		}
 		style := lipgloss.NewStyle().
-			Background(lipgloss.Color("63")).
-			Foreground(lipgloss.Color("15")).
+			Background(colorSelected).
+			Foreground(colorWhite).
 			Bold(true)
 		fmt.Fprint(w, style.Render(row))
 	} else {
-		fmt.Fprint(w, normalItemStyle.Render(formatPRRow(pi.pr, d.width)))
+		fmt.Fprint(w, normalItemStyle.Render(formatPRRow(pi.pr, pi.checks, d.width, d.timeFormat)))
 	}
 }
 
 type workflowDelegate struct{ width int }
 
 func (d workflowDelegate) Height() int                             { return 1 }
-func (d workflowDelegate) Spacing() int                           { return 0 }
+func (d workflowDelegate) Spacing() int                            { return 0 }
 func (d workflowDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d workflowDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
 	wi, ok := item.(workflowItem)
@@ -231,8 +503,8 @@ func (d workflowDelegate) Render(w io.Writer, m list.Model, index int, item list
 			row = row + strings.Repeat(" ", d.width-visWidth)
 		}
 		style := lipgloss.NewStyle().
-			Background(lipgloss.Color("63")).
-			Foreground(lipgloss.Color("15")).
+			Background(colorSelected).
+			Foreground(colorWhite).
 			Bold(true)
 		fmt.Fprint(w, style.Render(row))
 	} else {
@@ -240,18 +512,59 @@ func (d workflowDelegate) Render(w io.Writer, m list.Model, index int, item list
 	}
 }
 
+type artifactDelegate struct{ width int }
+
+func (d artifactDelegate) Height() int                             { return 1 }
+func (d artifactDelegate) Spacing() int                            { return 0 }
+func (d artifactDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d artifactDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	ai, ok := item.(artifactItem)
+	if !ok {
+		return
+	}
+	if index == m.Index() {
+		row := padToWidth("▶ "+formatArtifactRow(ai.a, d.width, false), d.width)
+		style := lipgloss.NewStyle().
+			Background(colorSelected).
+			Foreground(colorWhite).
+			Bold(true)
+		fmt.Fprint(w, style.Render(row))
+	} else {
+		fmt.Fprint(w, normalItemStyle.Render("  "+formatArtifactRow(ai.a, d.width, true)))
+	}
+}
+
+type historyDelegate struct {
+	width      int
+	timeFormat timeFormat
+}
+
+func (d historyDelegate) Height() int                             { return 1 }
+func (d historyDelegate) Spacing() int                            { return 0 }
+func (d historyDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d historyDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	hi, ok := item.(historyItem)
+	if !ok {
+		return
+	}
+	if index == m.Index() {
+		row := padToWidth("▶ "+formatHistoryRow(hi.rec, d.width, d.timeFormat), d.width)
+		style := lipgloss.NewStyle().
+			Background(colorSelected).
+			Foreground(colorWhite).
+			Bold(true)
+		fmt.Fprint(w, style.Render(row))
+	} else {
+		fmt.Fprint(w, normalItemStyle.Render("  "+formatHistoryRow(hi.rec, d.width, d.timeFormat)))
+	}
+}
+
 // ─── Row formatters ───────────────────────────────────────────────────────────
 
-func formatRunRow(r WorkflowRun, width int, selected bool) string {
-	const (
-		cursorW = 2
-		iconW   = 2
-		branchW = 22
-		eventW  = 11
-		ageW    = 8
-		gaps    = 4
-	)
-	nameW := max(8, width-cursorW-iconW-branchW-eventW-ageW-gaps)
+func formatRunRow(r WorkflowRun, p jobProgress, width int, selected bool, tf timeFormat) string {
+	ageW := tf.width()
+	durationW, actorW := runOptionalColumns(width, tf)
+	nameW := max(8, width-runCursorW-runIconW-runBranchW-actorW-runEventW-durationW-ageW-runGaps)
 
 	cursor := "  "
 	if selected {
@@ -259,34 +572,89 @@ func formatRunRow(r WorkflowRun, width int, selected bool) string {
 	}
 	icon := statusIcon(r.Status, r.Conclusion)
 	name := truncate(r.Name, nameW)
-	branch := truncate(r.HeadBranch, branchW)
-	event := truncate(r.Event, eventW)
-	age := relativeTime(r.CreatedAt)
+	if progress := p.label(); progress != "" && isRunning(r.Status) {
+		name = truncate(r.Name, max(1, nameW-len(progress)-1)) + " " + styleDim.Render(progress)
+	} else if r.Status == "waiting" {
+		name = truncate(r.Name, max(1, nameW-len(awaitingReviewLabel)-1)) + " " + styleWarn.Render(awaitingReviewLabel)
+	}
+	branch := truncate(r.HeadBranch, runBranchW)
+	event := truncate(r.Event, runEventW)
+	actor := optionalCell(r.Actor.Login, actorW)
+	duration := optionalCell(runDuration(r), durationW)
+	age := relativeTime(r.CreatedAt, tf)
 
-	return cursor + " " + icon + " " + padRight(name, nameW) + " " + padRight(branch, branchW) + " " + padRight(event, eventW) + " " + padRight(age, ageW)
+	return cursor + " " + icon + " " + padRight(name, nameW) + " " + padRight(branch, runBranchW) + " " + actor + padRight(event, runEventW) + " " + duration + padRight(age, ageW)
 }
 
-func formatRunRowPlain(r WorkflowRun, width int) string {
-	const (
-		cursorW = 2
-		iconW   = 2
-		branchW = 22
-		eventW  = 11
-		ageW    = 8
-		gaps    = 4
-	)
-	nameW := max(8, width-cursorW-iconW-branchW-eventW-ageW-gaps)
+func formatRunRowPlain(r WorkflowRun, p jobProgress, width int, tf timeFormat) string {
+	ageW := tf.width()
+	durationW, actorW := runOptionalColumns(width, tf)
+	nameW := max(8, width-runCursorW-runIconW-runBranchW-actorW-runEventW-durationW-ageW-runGaps)
 
 	icon := getPlainStatusIcon(r.Status, r.Conclusion)
 	name := truncate(r.Name, nameW)
-	branch := truncate(r.HeadBranch, branchW)
-	event := truncate(r.Event, eventW)
-	age := relativeTime(r.CreatedAt)
+	if progress := p.label(); progress != "" && isRunning(r.Status) {
+		name = truncate(r.Name, max(1, nameW-len(progress)-1)) + " " + progress
+	} else if r.Status == "waiting" {
+		name = truncate(r.Name, max(1, nameW-len(awaitingReviewLabel)-1)) + " " + awaitingReviewLabel
+	}
+	branch := truncate(r.HeadBranch, runBranchW)
+	event := truncate(r.Event, runEventW)
+	actor := optionalCell(r.Actor.Login, actorW)
+	duration := optionalCell(runDuration(r), durationW)
+	age := relativeTime(r.CreatedAt, tf)
+
+	return "▶  " + icon + " " + padRight(name, nameW) + " " + padRight(branch, runBranchW) + " " + actor + padRight(event, runEventW) + " " + duration + padRight(age, ageW)
+}
 
-	return "▶  " + icon + " " + padRight(name, nameW) + " " + padRight(branch, branchW) + " " + padRight(event, eventW) + " " + padRight(age, ageW)
+// awaitingReviewLabel marks runs held by an environment's required reviewers.
+const awaitingReviewLabel = "awaiting review"
+
+// Column widths shared by the runs list rows and their header. runGaps counts
+// the single spaces between the fixed columns; the optional duration and
+// actor widths include their own gap.
+const (
+	runCursorW   = 2
+	runIconW     = 2
+	runBranchW   = 22
+	runEventW    = 11
+	runGaps      = 4
+	runDurationW = 11
+	runActorW    = 15
+)
+
+// runNameMinW is the narrowest the runs list's name column gets before the
+// optional columns are dropped to make room.
+const runNameMinW = 20
+
+// runOptionalColumns returns the widths, including their gap, of the runs
+// list's duration and actor columns. Each is 0 when the terminal is too narrow
+// to show it; the duration column goes last.
+func runOptionalColumns(width int, tf timeFormat) (durationW, actorW int) {
+	const fixedW = runCursorW + runIconW + runBranchW + runEventW + runGaps
+	free := width - fixedW - tf.width() - runNameMinW
+	if free >= runDurationW {
+		durationW = runDurationW
+		free -= durationW
+	}
+	if free >= runActorW {
+		actorW = runActorW
+	}
+	return durationW, actorW
 }
 
-func formatJobRow(j Job, width int, selected bool) string {
+// optionalCell renders s padded to an optional column of width w including
+// its gap, empty when the column is hidden.
+func optionalCell(s string, w int) string {
+	if w == 0 {
+		return ""
+	}
+	return padRight(truncate(s, w-1), w-1) + " "
+}
+
+// formatJobRow renders a job row; a non-empty refreshIcon marks the job as
+// being refreshed and replaces its status icon.
+func formatJobRow(j Job, width int, selected bool, refreshIcon string) string {
 	const (
 		cursorW   = 2
 		iconW     = 2
@@ -303,21 +671,17 @@ func formatJobRow(j Job, width int, selected bool) string {
 	icon := statusIcon(j.Status, j.Conclusion)
 	name := truncate(j.Name, nameW)
 	status := truncate(statusLabel(j.Status, j.Conclusion), statusW)
-
-	dur := ""
-	if !j.StartedAt.IsZero() {
-		end := j.CompletedAt
-		if end.IsZero() {
-			end = time.Now()
-		}
-		dur = end.Sub(j.StartedAt).Round(time.Second).String()
+	if refreshIcon != "" {
+		icon = statusInProgress.Render(refreshIcon)
+		status = "refreshing…"
 	}
-	duration := truncate(dur, durationW)
+
+	duration := truncate(jobDuration(j), durationW)
 
 	return cursor + " " + icon + " " + padRight(name, nameW) + " " + padRight(status, statusW) + " " + padRight(duration, durationW)
 }
 
-func formatJobRowPlain(j Job, width int) string {
+func formatJobRowPlain(j Job, width int, refreshIcon string) string {
 	const (
 		cursorW   = 2
 		iconW     = 2
@@ -330,58 +694,103 @@ func formatJobRowPlain(j Job, width int) string {
 	icon := getPlainStatusIcon(j.Status, j.Conclusion)
 	name := truncate(j.Name, nameW)
 	status := truncate(statusLabel(j.Status, j.Conclusion), statusW)
-
-	dur := ""
-	if !j.StartedAt.IsZero() {
-		end := j.CompletedAt
-		if end.IsZero() {
-			end = time.Now()
-		}
-		dur = end.Sub(j.StartedAt).Round(time.Second).String()
+	if refreshIcon != "" {
+		icon = refreshIcon
+		status = "refreshing…"
 	}
-	duration := truncate(dur, durationW)
+
+	duration := truncate(jobDuration(j), durationW)
 
 	return "▶  " + icon + " " + padRight(name, nameW) + " " + padRight(status, statusW) + " " + padRight(duration, durationW)
 }
 
-func formatPRRow(pr PullRequest, width int) string {
+// checksStatus maps a PR's checks rollup onto the status and conclusion its
+// icon is drawn with; a PR without checks gets the pending icon.
+func checksStatus(r ChecksRollup) (status, conclusion string) {
+	switch {
+	case r.Total == 0:
+		return "", ""
+	case r.State() == "failure":
+		return "completed", "failure"
+	case r.State() == "pending":
+		return "in_progress", ""
+	case r.State() == "cancelled":
+		return "completed", "cancelled"
+	default:
+		return "completed", "success"
+	}
+}
+
+// prTitle renders a PR's title padded to width, marked when it can't be
+// merged and, dimmed when styled, when it is a draft.
+func prTitle(pr PullRequest, width int, styled bool) string {
+	var conflict, draft string
+	if pr.conflicted() {
+		conflict = icons.conflict + " "
+	}
+	if pr.Draft {
+		draft = "[draft] "
+	}
+	w := max(1, width-lipgloss.Width(conflict)-len(draft))
+	title := padRight(truncate(pr.Title, w), w)
+	if styled {
+		if conflict != "" {
+			conflict = styleWarn.Render(conflict)
+		}
+		if pr.Draft {
+			draft = styleDim.Render(draft)
+			title = styleDim.Render(title)
+		}
+	}
+	return conflict + draft + title
+}
+
+func formatPRRow(pr PullRequest, checks *ChecksRollup, width int, tf timeFormat) string {
 	const (
 		cursorW = 3
 		numW    = 6
 		branchW = 18
 		authorW = 14
-		ageW    = 8
 		gaps    = 4
 	)
+	ageW := tf.width()
 	titleW := max(8, width-cursorW-numW-branchW-authorW-ageW-gaps)
 
 	num := truncate(fmt.Sprintf("#%d", pr.Number), numW)
-	title := truncate(pr.Title, titleW)
+	title := prTitle(pr, titleW, true)
 	branch := truncate(pr.Head.Ref, branchW)
 	author := truncate(pr.User.Login, authorW)
-	age := relativeTime(pr.UpdatedAt)
+	age := relativeTime(pr.UpdatedAt, tf)
+	icon := " "
+	if checks != nil {
+		icon = statusIcon(checksStatus(*checks))
+	}
 
-	return "    " + padRight(num, numW) + " " + padRight(title, titleW) + " " + padRight(branch, branchW) + " " + padRight(author, authorW) + " " + padRight(age, ageW)
+	return "  " + icon + " " + padRight(num, numW) + " " + title + " " + padRight(branch, branchW) + " " + padRight(author, authorW) + " " + padRight(age, ageW)
 }
 
-func formatPRRowPlain(pr PullRequest, width int) string {
+func formatPRRowPlain(pr PullRequest, checks *ChecksRollup, width int, tf timeFormat) string {
 	const (
 		cursorW = 3
 		numW    = 6
 		branchW = 18
 		authorW = 14
-		ageW    = 8
 		gaps    = 4
 	)
+	ageW := tf.width()
 	titleW := max(8, width-cursorW-numW-branchW-authorW-ageW-gaps)
 
 	num := truncate(fmt.Sprintf("#%d", pr.Number), numW)
-	title := truncate(pr.Title, titleW)
+	title := prTitle(pr, titleW, false)
 	branch := truncate(pr.Head.Ref, branchW)
 	author := truncate(pr.User.Login, authorW)
-	age := relativeTime(pr.UpdatedAt)
+	age := relativeTime(pr.UpdatedAt, tf)
+	icon := " "
+	if checks != nil {
+		icon = getPlainStatusIcon(checksStatus(*checks))
+	}
 
-	return "▶   " + padRight(num, numW) + " " + padRight(title, titleW) + " " + padRight(branch, branchW) + " " + padRight(author, authorW) + " " + padRight(age, ageW)
+	return "▶ " + icon + " " + padRight(num, numW) + " " + title + " " + padRight(branch, branchW) + " " + padRight(author, authorW) + " " + padRight(age, ageW)
 }
 
 func formatWorkflowRow(wf Workflow, width int) string {
@@ -416,6 +825,80 @@ func formatWorkflowRowPlain(wf Workflow, width int) string {
 	return "▶   " + padRight(truncate(filename, fileW), fileW) + " " + truncate(wf.Name, nameW)
 }
 
+// artifactColumns returns the widths of the artifact list columns; the name
+// takes the remaining space.
+func artifactColumns(width int) (nameW, sizeW, expiryW int) {
+	const (
+		cursorW = 2
+		gaps    = 2
+	)
+	sizeW, expiryW = 10, 18
+	nameW = max(8, width-cursorW-sizeW-expiryW-gaps)
+	return
+}
+
+// formatArtifactRow renders an artifact without the cursor column. Expired
+// artifacts are dimmed when styled is set.
+func formatArtifactRow(a Artifact, width int, styled bool) string {
+	nameW, sizeW, expiryW := artifactColumns(width)
+	expiry := "expired"
+	if !a.Expired && !a.ExpiresAt.IsZero() {
+		expiry = "expires " + a.ExpiresAt.Local().Format("2006-01-02")
+	}
+	row := padRight(truncate(a.Name, nameW), nameW) + " " +
+		padRight(formatSize(a.SizeInBytes), sizeW) + " " +
+		truncate(expiry, expiryW)
+	if styled && a.Expired {
+		return styleDim.Render(row)
+	}
+	return row
+}
+
+// formatSize renders a byte count with a binary unit, e.g. 12.3 MiB.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// historyColumns returns the widths of the dispatch history columns; inputs
+// take the remaining space.
+func historyColumns(width int, tf timeFormat) (ageW, wfW, refW, byW, inputsW int) {
+	const (
+		cursorW = 2
+		gaps    = 4
+	)
+	ageW, wfW, refW, byW = tf.width(), 24, 20, 14
+	inputsW = max(8, width-cursorW-ageW-wfW-refW-byW-gaps)
+	return
+}
+
+// formatHistoryRow renders a dispatch record without the cursor column.
+func formatHistoryRow(r dispatchRecord, width int, tf timeFormat) string {
+	ageW, wfW, refW, byW, inputsW := historyColumns(width, tf)
+	keys := make([]string, 0, len(r.Inputs))
+	for k := range r.Inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	inputs := make([]string, len(keys))
+	for i, k := range keys {
+		inputs[i] = k + "=" + r.Inputs[k]
+	}
+	return padRight(relativeTime(r.At, tf), ageW) + " " +
+		padRight(truncate(r.Workflow, wfW), wfW) + " " +
+		padRight(truncate(r.Ref, refW), refW) + " " +
+		padRight(truncate(r.By, byW), byW) + " " +
+		truncate(strings.Join(inputs, " "), inputsW)
+}
+
 // ─── Utilities ────────────────────────────────────────────────────────────────
 
 // padRight pads the string with spaces on the right to reach length n.
@@ -450,38 +933,233 @@ func truncate(s string, n int) string {
 	return string(runes[:n-3]) + "..."
 }
 
-func relativeTime(t time.Time) string {
+// jobDuration returns how long a job has been running, or ran, rounded to the
+// second. Returns "" for jobs that have not started.
+func jobDuration(j Job) string {
+	if j.StartedAt.IsZero() {
+		return ""
+	}
+	end := j.CompletedAt
+	if end.IsZero() {
+		end = clockNow()
+	}
+	return end.Sub(j.StartedAt).Round(time.Second).String()
+}
+
+// stepDuration is how long a step took, or has been running; "" for steps
+// that haven't started.
+func stepDuration(s Step) string {
+	if s.StartedAt.IsZero() {
+		return ""
+	}
+	end := s.CompletedAt
+	if end.IsZero() {
+		if s.Status == "completed" {
+			return ""
+		}
+		end = clockNow()
+	}
+	return max(0, end.Sub(s.StartedAt)).Round(time.Second).String()
+}
+
+// failedStep returns the first step of job that failed or timed out.
+func failedStep(job Job) (Step, bool) {
+	for _, s := range job.Steps {
+		if s.Status == "completed" && (s.Conclusion == "failure" || s.Conclusion == "timed_out") {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// stepURL links to a step of the job on GitHub, which expands the step and
+// scrolls to it.
+func stepURL(job Job, s Step) string {
+	return fmt.Sprintf("%s#step:%d:1", job.HTMLURL, s.Number)
+}
+
+// runDuration is how long a run has taken, from its creation until its last
+// update once completed, or until now while it is queued or running.
+func runDuration(r WorkflowRun) string {
+	end := r.UpdatedAt
+	if r.Status != "completed" || end.IsZero() {
+		end = clockNow()
+	}
+	return max(0, end.Sub(r.CreatedAt)).Round(time.Second).String()
+}
+
+// firstLine returns s up to the first newline, e.g. a commit message's subject.
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+// clockNow is the reference time for ages and durations. main points it at
+// the GitHub client so the server's clock wins over a drifting local one.
+var clockNow = time.Now
+
+// relativeTime formats t for the age columns in the configured format.
+// Relative formats show "just now" for anything under a minute.
+func relativeTime(t time.Time, f timeFormat) string {
 	if t.IsZero() {
 		return ""
 	}
-	d := time.Since(t)
-	switch {
-	case d < time.Minute:
+	if f == timeAbsolute {
+		return t.Local().Format("2006-01-02 15:04")
+	}
+	d := clockNow().Sub(t)
+	if d < time.Minute {
 		return "just now"
+	}
+	var n int
+	var unit string
+	switch {
 	case d < time.Hour:
-		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+		n, unit = int(d.Minutes()), "minute"
 	case d < 24*time.Hour:
-		return fmt.Sprintf("%dh ago", int(d.Hours()))
+		n, unit = int(d.Hours()), "hour"
 	default:
-		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+		n, unit = int(d.Hours()/24), "day"
+	}
+	if f == timeVerbose {
+		if n != 1 {
+			unit += "s"
+		}
+		return fmt.Sprintf("%d %s ago", n, unit)
+	}
+	return fmt.Sprintf("%d%c ago", n, unit[0])
+}
+
+// refRow is one line of the branch list in the ref picker: a branch or, with
+// ref_groups enabled, the header of a branch prefix group.
+type refRow struct {
+	name      string // branch name, or the group prefix including its slash
+	header    bool
+	member    bool // branch listed under a group header
+	count     int  // branches in the group (headers only)
+	collapsed bool
+}
+
+// groupRefs arranges branches under their first path segment (feature/,
+// release/, ...). Branches without a prefix, or whose prefix no other branch
+// shares, come first. Collapsed groups hide their branches unless filtering,
+// so matches are never hidden.
+func groupRefs(refs []string, collapsed map[string]bool, filtering bool) []refRow {
+	groups := make(map[string][]string)
+	var order []string
+	for _, r := range refs {
+		if i := strings.Index(r, "/"); i > 0 {
+			prefix := r[:i+1]
+			if _, ok := groups[prefix]; !ok {
+				order = append(order, prefix)
+			}
+			groups[prefix] = append(groups[prefix], r)
+		}
 	}
+	var rows []refRow
+	for _, r := range refs {
+		if i := strings.Index(r, "/"); i <= 0 || len(groups[r[:i+1]]) < 2 {
+			rows = append(rows, refRow{name: r})
+		}
+	}
+	for _, prefix := range order {
+		members := groups[prefix]
+		if len(members) < 2 {
+			continue
+		}
+		hidden := collapsed[prefix] && !filtering
+		rows = append(rows, refRow{name: prefix, header: true, count: len(members), collapsed: hidden})
+		if hidden {
+			continue
+		}
+		for _, r := range members {
+			rows = append(rows, refRow{name: r, member: true})
+		}
+	}
+	return rows
 }
 
-// filterRefs returns the subset of refs whose name contains the lower-cased filter string.
+// filterRefs returns the subset of refs matching the lower-cased filter string.
+// Contains mode keeps refs whose name contains the filter. Fuzzy mode keeps refs
+// containing the filter's characters in order, best matches first: characters
+// matched at the start of a path segment or word count most, so "tf123" ranks
+// team/feature/ticket-123 above unrelated names that merely contain the letters.
 // Returns the original slice unchanged when filter is empty.
-func filterRefs(refs []string, lower string) []string {
+func filterRefs(refs []string, lower string, mode refMatch) []string {
 	if lower == "" {
 		return refs
 	}
-	var out []string
+	if mode != refMatchFuzzy {
+		var out []string
+		for _, r := range refs {
+			if strings.Contains(strings.ToLower(r), lower) {
+				out = append(out, r)
+			}
+		}
+		return out
+	}
+
+	type scored struct {
+		ref   string
+		score int
+	}
+	var matches []scored
 	for _, r := range refs {
-		if strings.Contains(strings.ToLower(r), lower) {
-			out = append(out, r)
+		if score, ok := fuzzyScore(strings.ToLower(r), lower); ok {
+			matches = append(matches, scored{r, score})
 		}
 	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.ref
+	}
 	return out
 }
 
+// fuzzyScore reports whether all runes of pattern appear in name in order, and
+// scores the match: +2 for a rune at a segment start (after / - _ . or at the
+// beginning), +1 for a rune directly following the previous match.
+func fuzzyScore(name, pattern string) (int, bool) {
+	score := 0
+	prev := -2
+	p := []rune(pattern)
+	pi := 0
+	nr := []rune(name)
+	for i, r := range nr {
+		if pi == len(p) {
+			break
+		}
+		if r != p[pi] {
+			continue
+		}
+		if i == 0 || strings.ContainsRune("/-_.", nr[i-1]) {
+			score += 2
+		}
+		if i == prev+1 {
+			score++
+		}
+		prev = i
+		pi++
+	}
+	return score, pi == len(p)
+}
+
+// qualifyRef returns name unchanged unless a ref of the other kind has the same
+// name (a branch and a tag both called "v1"). GitHub would resolve the plain
+// name to the branch, so it is then qualified with prefix ("refs/heads/" or
+// "refs/tags/") to dispatch on the section the user picked from.
+func qualifyRef(name, prefix string, others []string) string {
+	for _, o := range others {
+		if o == name {
+			return prefix + name
+		}
+	}
+	return name
+}
+
 // buildDispatchFormFields constructs the form fields for a workflow dispatch form.
 // Field 0 is always the ref/branch/tag field; subsequent fields correspond to
 // the workflow's workflow_dispatch inputs in the order they appear in the YAML.
@@ -537,33 +1215,94 @@ func buildDispatchFormFields(inputs []WorkflowInput, defaultRef string) []formFi
 		default:
 			f.input = newInput(inp.Default)
 		}
+		f.defaultValue = f.input.Value()
 		fields = append(fields, f)
 	}
 	return fields
 }
 
+// prefillDispatchForm fills the form with the ref and inputs of an earlier
+// dispatch. Inputs the workflow no longer has, and values a choice or boolean
+// no longer accepts, keep the YAML default.
+func prefillDispatchForm(fields []formField, rec dispatchRecord) {
+	if rec.Ref != "" {
+		fields[0].input.SetValue(rec.Ref)
+	}
+	for i := 1; i < len(fields); i++ {
+		f := &fields[i]
+		val, ok := rec.Inputs[f.label]
+		if !ok {
+			continue
+		}
+		switch f.fieldType {
+		case "boolean":
+			if val != "true" && val != "false" {
+				continue
+			}
+		case "choice":
+			idx := slices.Index(f.options, val)
+			if idx < 0 {
+				continue
+			}
+			f.optionIdx = idx
+		case "string":
+			if strings.Contains(val, "\n") {
+				f.expand(val)
+				continue
+			}
+		}
+		f.input.SetValue(val)
+	}
+}
+
 // ─── Entry point ──────────────────────────────────────────────────────────────
 
 func main() {
 	var repoPath string
 	var debugFile string
+	var linkRunID, linkJobID int64
+	var watchRunID int64
+	var repoFlag string
+	var jsonKind string
+	var jsonRunID int64
+	var noColorFlag bool
+	pollFlags := map[string]string{}
 
 	args := os.Args[1:]
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		switch arg {
 		case "-h", "--help", "help":
-			fmt.Println("Usage: tgh [REPO_PATH] [--debug <filename>]")
+			fmt.Println("Usage: tgh [REPO_PATH | --repo <owner/name>] [--run <id> | --job <id> | --watch <id>] [--poll-runs|--poll-jobs|--poll-logs <interval>] [--no-color] [--debug <filename>]")
+			fmt.Println("       tgh [REPO_PATH | --repo <owner/name>] --json runs | jobs <run id> | prs")
 			fmt.Println()
 			fmt.Println("tgh is a terminal UI for browsing GitHub Actions job logs")
 			fmt.Println()
 			fmt.Println("Arguments:")
 			fmt.Println("  REPO_PATH          Optional path to a git repository")
+			fmt.Println("  --repo <repo>      Use the owner/name (or host/owner/name) repository, not the")
+			fmt.Println("                     current directory's")
+			fmt.Println("  --run <id>         Open the jobs of the given workflow run")
+			fmt.Println("  --job <id>         Open the logs of the given job")
+			fmt.Println("  --watch <id>       Wait for the given run to finish without the UI, then ring the")
+			fmt.Println("                     bell and show a desktop notification; exits 0 if it succeeded")
+			fmt.Println("  --no-color         Plain output without colors, also set by NO_COLOR")
 			fmt.Println("  --debug <filename> Write debug log to the given file")
+			fmt.Println("  --json <list>      Print the latest runs, a run's jobs or the open PRs as JSON and exit")
+			fmt.Println()
+			fmt.Println("Polling (intervals like 5s or 1m, at least 1s; flags override env):")
+			fmt.Printf("  --poll-runs <interval>  Refresh the runs list (TGH_POLL_RUNS, default %s)\n", defaultPollIntervals.runs)
+			fmt.Printf("  --poll-jobs <interval>  Refresh the open run's jobs (TGH_POLL_JOBS, default %s)\n", defaultPollIntervals.jobs)
+			fmt.Printf("  --poll-logs <interval>  Refresh the open job's log (TGH_POLL_LOGS, default %s)\n", defaultPollIntervals.logs)
 			fmt.Println()
 			fmt.Println("Examples:")
 			fmt.Println("  tgh                         # Run in current directory")
 			fmt.Println("  tgh /path/to/repo           # Run in specified directory")
+			fmt.Println("  tgh --repo cli/cli          # Run against a repository you haven't cloned")
+			fmt.Println("  tgh --job 12345             # Open a job's logs")
+			fmt.Println("  tgh --watch 12345 && deploy # Continue once a run has succeeded")
+			fmt.Println("  tgh --json jobs 12345       # Print a run's jobs as JSON")
+			fmt.Println("  tgh --poll-runs 60s         # Poll the runs list once a minute")
 			fmt.Println("  tgh --debug /tmp/tgh.log    # Run with debug logging")
 			os.Exit(0)
 		case "--debug":
@@ -573,24 +1312,152 @@ func main() {
 			}
 			i++
 			debugFile = args[i]
+		case "--no-color":
+			noColorFlag = true
+		case "--poll-runs", "--poll-jobs", "--poll-logs":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires an interval argument\n", arg)
+				os.Exit(1)
+			}
+			i++
+			pollFlags[arg] = args[i]
+		case "--repo":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "Error: --repo requires an owner/name argument")
+				os.Exit(1)
+			}
+			i++
+			repoFlag = args[i]
+		case "--json":
+			if i+1 >= len(args) || !slices.Contains(jsonKinds, args[i+1]) {
+				fmt.Fprintln(os.Stderr, "Error: --json requires runs, jobs <run id> or prs")
+				os.Exit(1)
+			}
+			i++
+			jsonKind = args[i]
+			if jsonKind == "jobs" {
+				if i+1 >= len(args) {
+					fmt.Fprintln(os.Stderr, "Error: --json jobs requires a run ID argument")
+					os.Exit(1)
+				}
+				i++
+				id, err := strconv.ParseInt(args[i], 10, 64)
+				if err != nil || id <= 0 {
+					fmt.Fprintf(os.Stderr, "Error: --json jobs: invalid run ID %q\n", args[i])
+					os.Exit(1)
+				}
+				jsonRunID = id
+			}
+		case "--run", "--job", "--watch":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s requires an ID argument\n", arg)
+				os.Exit(1)
+			}
+			i++
+			id, err := strconv.ParseInt(args[i], 10, 64)
+			if err != nil || id <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: %s: invalid ID %q\n", arg, args[i])
+				os.Exit(1)
+			}
+			switch arg {
+			case "--run":
+				linkRunID = id
+			case "--job":
+				linkJobID = id
+			default:
+				watchRunID = id
+			}
 		default:
 			repoPath = arg
 		}
 	}
 
+	poll := defaultPollIntervals
+	if err := poll.applyEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	for flag, v := range pollFlags {
+		d, err := parsePollInterval(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", flag, err)
+			os.Exit(1)
+		}
+		*poll.field(flag) = d
+	}
+
 	initDebugLog(debugFile)
 
-	client, err := NewGitHubClient(repoPath)
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	useIcons(cfg.Icons)
+	useTheme(cfg.Theme)
+	if noColorRequested(noColorFlag) {
+		useNoColor()
+	}
+
+	var last *session
+	if cfg.RestoreSession {
+		last = loadSession()
+	}
+
+	if repoFlag != "" && repoPath != "" {
+		fmt.Fprintln(os.Stderr, "Error: use either REPO_PATH or --repo, not both")
+		os.Exit(1)
+	}
+	if watchRunID != 0 && (linkRunID != 0 || linkJobID != 0) {
+		fmt.Fprintln(os.Stderr, "Error: use either --watch or --run/--job, not both")
+		os.Exit(1)
+	}
+	if jsonKind != "" && (watchRunID != 0 || linkRunID != 0 || linkJobID != 0) {
+		fmt.Fprintln(os.Stderr, "Error: use either --json or --run/--job/--watch, not both")
+		os.Exit(1)
+	}
+	var client *GitHubClient
+	if repoFlag != "" {
+		client, err = NewGitHubClientForRepo(repoFlag)
+	} else {
+		client, err = NewGitHubClient(repoPath)
+	}
+	if errors.Is(err, errRepoNotDetected) && repoPath == "" && jsonKind == "" {
+		// Not in a repository: reopen the last session's repository, or let the
+		// user pick one instead of failing.
+		if last != nil {
+			client, err = NewGitHubClient("https://" + last.Repo)
+		} else {
+			var picked string
+			if picked, err = runRepoPicker(); err == nil {
+				client, err = NewGitHubClient("https://" + picked)
+			}
+		}
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
+	clockNow = client.Now
+	if jsonKind != "" {
+		os.Exit(printJSON(client, jsonKind, jsonRunID))
+	}
+	if watchRunID != 0 {
+		os.Exit(watchRun(client, watchRunID, poll.runs))
+	}
+	repoID := client.host + "/" + client.owner + "/" + client.repo
+	if err := saveRecentRepo(repoID); err != nil {
+		dbg("saving recent repo: %v", err)
+	}
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
+	if icons == asciiIcons {
+		s.Spinner = spinner.Line
+	}
 	s.Style = lipgloss.NewStyle().Foreground(colorAmber)
 
-	rdel := runDelegate{width: 80}
+	rdel := runDelegate{width: 80, timeFormat: cfg.TimeFormat}
 	runsList := list.New([]list.Item{}, rdel, 80, 20)
 	runsList.SetShowTitle(false)
 	runsList.SetShowStatusBar(false)
@@ -603,15 +1470,15 @@ func main() {
 	jobsList.SetShowTitle(false)
 	jobsList.SetShowStatusBar(false)
 	jobsList.SetShowPagination(false)
-	jobsList.SetFilteringEnabled(false)
+	jobsList.SetFilteringEnabled(true)
 	jobsList.DisableQuitKeybindings()
 
-	pdel := prDelegate{width: 80}
+	pdel := prDelegate{width: 80, timeFormat: cfg.TimeFormat}
 	prsList := list.New([]list.Item{}, pdel, 80, 20)
 	prsList.SetShowTitle(false)
 	prsList.SetShowStatusBar(false)
 	prsList.SetShowPagination(false)
-	prsList.SetFilteringEnabled(false)
+	prsList.SetFilteringEnabled(true)
 	prsList.DisableQuitKeybindings()
 
 	wdel := workflowDelegate{width: 80}
@@ -619,27 +1486,77 @@ func main() {
 	workflowsList.SetShowTitle(false)
 	workflowsList.SetShowStatusBar(false)
 	workflowsList.SetShowPagination(false)
-	workflowsList.SetFilteringEnabled(false)
+	workflowsList.SetFilteringEnabled(true)
 	workflowsList.DisableQuitKeybindings()
 
+	adel := artifactDelegate{width: 80}
+	artifactsList := list.New([]list.Item{}, adel, 80, 20)
+	artifactsList.SetShowTitle(false)
+	artifactsList.SetShowStatusBar(false)
+	artifactsList.SetShowPagination(false)
+	artifactsList.SetFilteringEnabled(false)
+	artifactsList.DisableQuitKeybindings()
+
+	annotationsList := list.New([]list.Item{}, annotationDelegate{width: 80}, 80, 20)
+	annotationsList.SetShowTitle(false)
+	annotationsList.SetShowStatusBar(false)
+	annotationsList.SetShowPagination(false)
+	annotationsList.SetFilteringEnabled(false)
+	annotationsList.DisableQuitKeybindings()
+
+	hdel := historyDelegate{width: 80, timeFormat: cfg.TimeFormat}
+	historyList := list.New([]list.Item{}, hdel, 80, 20)
+	historyList.SetShowTitle(false)
+	historyList.SetShowStatusBar(false)
+	historyList.SetShowPagination(false)
+	historyList.SetFilteringEnabled(false)
+	historyList.DisableQuitKeybindings()
+
 	vp := viewport.New(80, 20)
 
 	m := model{
-		state:          stateMenu,
-		client:         client,
-		runsList:       runsList,
-		jobsList:       jobsList,
-		prsList:        prsList,
-		workflowsList:  workflowsList,
-		logViewport:    vp,
-		spinner:        s,
-		autoScroll:     true,
-		lastJobsForRun: make(map[int64][]Job),
-	}
-
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+		state:           stateMenu,
+		client:          client,
+		config:          cfg,
+		poll:            poll,
+		runsList:        runsList,
+		jobsList:        jobsList,
+		prsList:         prsList,
+		workflowsList:   workflowsList,
+		historyList:     historyList,
+		artifactsList:   artifactsList,
+		annotationsList: annotationsList,
+		logViewport:     vp,
+		summaryViewport: viewport.New(80, 20),
+		diffViewport:    viewport.New(80, 20),
+		spinner:         s,
+		autoScroll:      true,
+		lastJobsForRun:  make(map[int64][]Job),
+		envVars:         make(map[string][]EnvVariable),
+		logCache:        newLogCache(cfg.logCacheBytes()),
+		logPrefetching:  make(map[int64]bool),
+		runProgress:     make(map[int64]jobProgress),
+		prChecks:        make(map[string]ChecksRollup),
+		prMergeable:     make(map[int]PRStatus),
+		progressIn:      make(map[int64]bool),
+	}
+
+	switch {
+	case linkRunID != 0 || linkJobID != 0:
+		m.beginDeepLink(linkRunID, linkJobID)
+	case last != nil && last.Repo == repoID:
+		m.beginRestore(last)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithOutput(termOutput))
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
+	if fm, ok := final.(model); ok && cfg.RestoreSession {
+		if err := saveSession(fm.sessionSnapshot()); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: could not save session:", err)
+		}
+	}
 }
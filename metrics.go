@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// runMetrics summarises queueing and concurrency for a run, derived from its jobs.
+type runMetrics struct {
+	jobs        int
+	queueTotal  time.Duration // sum of per-job wait from creation to start
+	longestWait time.Duration
+	longestJob  string
+	ongoing     int           // jobs that have not started yet
+	maxParallel int           // peak number of jobs running at the same time
+	wallClock   time.Duration // run creation to completion (or now)
+	jobTime     time.Duration // sum of job durations
+}
+
+// computeRunMetrics derives queue time and peak parallelism from already-fetched jobs.
+// Jobs without a creation time are measured from the run's creation time.
+// Jobs that have not started count their wait up to now and are reported as ongoing.
+func computeRunMetrics(run WorkflowRun, jobs []Job, now time.Time) runMetrics {
+	rm := runMetrics{jobs: len(jobs)}
+	if !run.CreatedAt.IsZero() {
+		end := now
+		if run.Status == "completed" && run.UpdatedAt.After(run.CreatedAt) {
+			end = run.UpdatedAt
+		}
+		rm.wallClock = end.Sub(run.CreatedAt)
+	}
+
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	var edges []edge
+
+	for _, j := range jobs {
+		created := j.CreatedAt
+		if created.IsZero() {
+			created = run.CreatedAt
+		}
+		if j.Status == "completed" && j.StartedAt.IsZero() {
+			continue // skipped or cancelled before it ever started
+		}
+		started := j.StartedAt
+		if started.IsZero() {
+			rm.ongoing++
+			started = now
+		}
+		if !created.IsZero() && started.After(created) {
+			wait := started.Sub(created)
+			rm.queueTotal += wait
+			if wait > rm.longestWait {
+				rm.longestWait = wait
+				rm.longestJob = j.Name
+			}
+		}
+		if !j.StartedAt.IsZero() {
+			end := j.CompletedAt
+			if end.IsZero() {
+				end = now
+			}
+			edges = append(edges, edge{j.StartedAt, 1}, edge{end, -1})
+			rm.jobTime += end.Sub(j.StartedAt)
+		}
+	}
+
+	// Sweep start/end edges; ends sort before starts at the same instant so
+	// back-to-back jobs are not counted as overlapping.
+	sort.Slice(edges, func(a, b int) bool {
+		if edges[a].at.Equal(edges[b].at) {
+			return edges[a].delta < edges[b].delta
+		}
+		return edges[a].at.Before(edges[b].at)
+	})
+	running := 0
+	for _, e := range edges {
+		running += e.delta
+		rm.maxParallel = max(rm.maxParallel, running)
+	}
+	return rm
+}
+
+// runMetricsLines renders the metrics panel shown above the jobs list.
+func (m model) runMetricsLines() []string {
+	rm := computeRunMetrics(m.selectedRun, m.lastJobsForRun[m.selectedRun.ID], clockNow())
+
+	queue := fmt.Sprintf("%s total across %d jobs", rm.queueTotal.Round(time.Second), rm.jobs)
+	if rm.longestJob != "" {
+		queue += fmt.Sprintf(" · longest %s (%s)", rm.longestWait.Round(time.Second), truncate(rm.longestJob, 30))
+	}
+	if rm.ongoing > 0 {
+		queue += " · " + styleWarn.Render(fmt.Sprintf("%d ongoing", rm.ongoing))
+	}
+	parallel := fmt.Sprintf("%d jobs at peak", rm.maxParallel)
+
+	wall := rm.wallClock
+	if m.runTiming != nil && m.runTiming.RunDurationMS > 0 {
+		wall = time.Duration(m.runTiming.RunDurationMS) * time.Millisecond
+	}
+	duration := fmt.Sprintf("%s wall-clock · %s job time", wall.Round(time.Second), rm.jobTime.Round(time.Second))
+	compare := rm.jobTime
+	if m.runTiming != nil && m.runTiming.BillableMS() > 0 {
+		billable := time.Duration(m.runTiming.BillableMS()) * time.Millisecond
+		duration += fmt.Sprintf(" · %d billable min", int(math.Ceil(billable.Minutes())))
+		compare = billable
+	}
+	if wall > 0 && compare > 0 {
+		ratio := float64(compare) / float64(wall)
+		label := fmt.Sprintf(" (%.1f× wall-clock)", ratio)
+		// Flag heavy parallelism (well above 1×) or runs dominated by queueing.
+		if ratio >= 2 || ratio <= 0.5 {
+			duration += styleWarn.Render(label)
+		} else {
+			duration += styleDim.Render(label)
+		}
+	}
+
+	return []string{
+		" " + styleDim.Render(padRight("Queue time", 14)) + queue,
+		" " + styleDim.Render(padRight("Parallelism", 14)) + parallel,
+		" " + styleDim.Render(padRight("Duration", 14)) + duration,
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// errPickerCancelled is returned when the user leaves the repository picker
+// without choosing a repository.
+var errPickerCancelled = errors.New("no repository selected")
+
+// repoPicker is a small standalone program shown at startup when tgh is not
+// run inside a repository. It offers recently opened repositories and accepts
+// a typed owner/repo (on the default host) or host/owner/repo.
+type repoPicker struct {
+	input       textinput.Model
+	recent      []string // host/owner/repo, most recent first
+	hosts       []string // hosts with credentials in the gh config
+	defaultHost string
+	index       int  // selected entry in matches()
+	navigated   bool // the user moved through the recent list since typing
+	choice      string
+	errMsg      string
+}
+
+// runRepoPicker asks the user for a repository and returns it as host/owner/repo.
+func runRepoPicker() (string, error) {
+	ti := textinput.New()
+	ti.Placeholder = "owner/repo"
+	ti.Prompt = "> "
+	ti.Focus()
+
+	host, _ := auth.DefaultHost()
+	p := repoPicker{
+		input:       ti,
+		recent:      loadRecentRepos(),
+		hosts:       auth.KnownHosts(),
+		defaultHost: host,
+	}
+	res, err := tea.NewProgram(p).Run()
+	if err != nil {
+		return "", err
+	}
+	if choice := res.(repoPicker).choice; choice != "" {
+		return choice, nil
+	}
+	return "", errPickerCancelled
+}
+
+// matches returns the recent repositories containing the typed text.
+func (p repoPicker) matches() []string {
+	filter := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	var out []string
+	for _, r := range p.recent {
+		if strings.Contains(strings.ToLower(r), filter) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// resolve turns typed text into host/owner/repo, or returns "" if it is not a
+// valid owner/repo or host/owner/repo.
+func (p repoPicker) resolve(text string) string {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(text), "/"), "/")
+	for _, part := range parts {
+		if part == "" {
+			return ""
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return p.defaultHost + "/" + parts[0] + "/" + parts[1]
+	case 3:
+		return strings.Join(parts, "/")
+	}
+	return ""
+}
+
+func (p repoPicker) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (p repoPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "esc":
+			return p, tea.Quit
+		case "up":
+			if p.index > 0 {
+				p.index--
+			}
+			p.navigated = true
+			return p, nil
+		case "down":
+			if p.index < len(p.matches())-1 {
+				p.index++
+			}
+			p.navigated = true
+			return p, nil
+		case "enter":
+			// A complete typed repository wins unless the user picked from the list.
+			typed := p.resolve(p.input.Value())
+			if matches := p.matches(); len(matches) > 0 && (p.navigated || typed == "") {
+				p.choice = matches[p.index]
+				return p, tea.Quit
+			}
+			if p.choice = typed; p.choice != "" {
+				return p, tea.Quit
+			}
+			p.errMsg = "Enter owner/repo or host/owner/repo"
+			return p, nil
+		}
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		p.errMsg = ""
+		p.navigated = false
+	}
+	if p.index >= len(p.matches()) {
+		p.index = max(0, len(p.matches())-1)
+	}
+	return p, cmd
+}
+
+func (p repoPicker) View() string {
+	var b strings.Builder
+	b.WriteString(appNameStyle.Render("tgh") + " " + styleHeader.Render("Select a repository") + "\n\n")
+	b.WriteString(styleDim.Render(" Not inside a GitHub repository. Type owner/repo or pick a recent one.") + "\n")
+	if len(p.hosts) > 0 {
+		b.WriteString(styleDim.Render(fmt.Sprintf(" Known hosts: %s (default %s)", strings.Join(p.hosts, ", "), p.defaultHost)) + "\n")
+	}
+	b.WriteString("\n " + p.input.View() + "\n")
+	if p.errMsg != "" {
+		b.WriteString(" " + styleError.Render(p.errMsg) + "\n")
+	}
+
+	matches := p.matches()
+	if len(matches) > 0 {
+		b.WriteString("\n" + colHeaderStyle.Render(" RECENT") + "\n")
+	}
+	for i, r := range matches {
+		if i == p.index {
+			b.WriteString(styleAccent.Render(" ▶ "+r) + "\n")
+		} else {
+			b.WriteString("   " + r + "\n")
+		}
+	}
+	b.WriteString("\n" + renderFooter([]string{"<enter> open", "<↑/↓> select", "<esc> quit"}) + "\n")
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pollIntervals are the delays between refreshes of the runs list, the open
+// run's jobs and the open job's log.
+type pollIntervals struct {
+	runs, jobs, logs time.Duration
+}
+
+var defaultPollIntervals = pollIntervals{runs: 10 * time.Second, jobs: 2 * time.Second, logs: 3 * time.Second}
+
+// minPollInterval keeps polling from hammering the API (or spinning at 0).
+const minPollInterval = time.Second
+
+// pollEnv maps the --poll-* flags to their environment variables.
+var pollEnv = map[string]string{
+	"--poll-runs": "TGH_POLL_RUNS",
+	"--poll-jobs": "TGH_POLL_JOBS",
+	"--poll-logs": "TGH_POLL_LOGS",
+}
+
+// field returns the interval set by the --poll-* flag name.
+func (p *pollIntervals) field(flag string) *time.Duration {
+	switch flag {
+	case "--poll-runs":
+		return &p.runs
+	case "--poll-jobs":
+		return &p.jobs
+	default:
+		return &p.logs
+	}
+}
+
+// applyEnv overrides the intervals set in the TGH_POLL_* variables.
+func (p *pollIntervals) applyEnv() error {
+	for flag, env := range pollEnv {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		d, err := parsePollInterval(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", env, err)
+		}
+		*p.field(flag) = d
+	}
+	return nil
+}
+
+// parsePollInterval parses a duration such as "30s" or "1m", or a plain
+// number of seconds.
+func parsePollInterval(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		secs, serr := strconv.ParseFloat(s, 64)
+		if serr != nil {
+			return 0, fmt.Errorf("invalid interval %q (use e.g. 5s or 1m)", s)
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	if d < minPollInterval {
+		return 0, fmt.Errorf("interval %q is below the minimum of %s", s, minPollInterval)
+	}
+	return d, nil
+}
+
+// maxPollBackoff caps the poll interval while requests keep failing.
+const maxPollBackoff = 5 * time.Minute
+
+// pollDelay is the poll interval base, doubled for each consecutive failure
+// up to maxPollBackoff.
+func (m model) pollDelay(base time.Duration) time.Duration {
+	d := base
+	for i := 0; i < m.pollFailures && d < maxPollBackoff; i++ {
+		d *= 2
+	}
+	if d > maxPollBackoff && base < maxPollBackoff {
+		d = maxPollBackoff
+	}
+	return d
+}
+
+// backOffPolling counts a failed request when polling is active and returns
+// the backed-off interval of the current view's poll loop.
+func (m *model) backOffPolling() (time.Duration, bool) {
+	logsPolling := m.state == stateLogs && isRunning(m.selectedJob.Status)
+	if !m.runsPolling && !m.jobsPolling && !logsPolling {
+		return 0, false
+	}
+	m.pollFailures++
+	switch {
+	case logsPolling:
+		return m.pollDelay(m.poll.logs), true
+	case m.state == stateJobs && m.jobsPolling:
+		return m.pollDelay(m.poll.jobs), true
+	case m.runsPolling:
+		return m.pollDelay(m.poll.runs), true
+	default:
+		return m.pollDelay(m.poll.jobs), true
+	}
+}
+
+// restartPolling reschedules the active poll loops with the current backoff,
+// dropping the ticks already scheduled at the previous interval.
+func (m *model) restartPolling() []tea.Cmd {
+	m.pollGen++
+	var cmds []tea.Cmd
+	if m.runsPolling {
+		cmds = append(cmds, m.runsPollCmd())
+	}
+	if m.jobsPolling {
+		cmds = append(cmds, m.jobsPollCmd())
+	}
+	if m.state == stateLogs && isRunning(m.selectedJob.Status) {
+		cmds = append(cmds, m.logPollCmd())
+	}
+	return cmds
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type loginMsg struct {
+	login string
+	err   error
+}
+
+func fetchLoginCmd(c *GitHubClient) tea.Cmd {
+	return func() tea.Msg {
+		login, err := c.CurrentLogin()
+		return loginMsg{login: login, err: err}
+	}
+}
+
+// prStatusesMsg carries the checks rollups and mergeability of the open pull
+// requests.
+type prStatusesMsg []PRStatus
+
+func fetchPRStatusesCmd(c *GitHubClient) tea.Cmd {
+	return func() tea.Msg {
+		statuses, err := c.ListPRStatuses()
+		if err != nil {
+			// Checks and mergeability are a convenience; keep the list free
+			// of their errors.
+			dbg("ListPRStatuses: %v", err)
+			return nil
+		}
+		return prStatusesMsg(statuses)
+	}
+}
+
+// withMergeability copies the mergeability fetched before for the same head
+// commit into pr, which the list endpoint returns without it.
+func (m model) withMergeability(pr PullRequest) PullRequest {
+	if known, ok := m.prMergeable[pr.Number]; ok && known.HeadSHA == pr.Head.SHA {
+		pr.Mergeable = known.Mergeable
+	}
+	return pr
+}
+
+// setPRs stores the loaded pull requests and lists them, only those opened by
+// the authenticated user while prsMine is set.
+func (m *model) setPRs(prs []PullRequest) tea.Cmd {
+	m.loadedPRs = prs
+	var items []list.Item
+	for _, pr := range prs {
+		if m.prsMine && pr.User.Login != m.login {
+			continue
+		}
+		item := prItem{pr: m.withMergeability(pr)}
+		if rollup, ok := m.prChecks[pr.Head.SHA]; ok {
+			item.checks = &rollup
+		}
+		items = append(items, item)
+	}
+	return m.prsList.SetItems(items)
+}
+
+// togglePRsMine switches between all pull requests and the user's own,
+// looking up the user's login the first time.
+func (m *model) togglePRsMine() tea.Cmd {
+	if m.login == "" {
+		m.statusMsg = "Looking up your login…"
+		return fetchLoginCmd(m.client)
+	}
+	m.prsMine = !m.prsMine
+	return m.setPRs(m.loadedPRs)
+}
+
+// prFilterLabel is the breadcrumb suffix naming the active PR filters.
+func (m model) prFilterLabel() string {
+	var label string
+	if m.prsMine {
+		label += " (mine)"
+	}
+	if m.prsList.FilterState() == list.FilterApplied {
+		label += fmt.Sprintf("  filter: %q", m.prsList.FilterValue())
+	}
+	return label
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// prReviewedMsg reports an approval or comment posted on a pull request, or
+// the error that prevented it.
+type prReviewedMsg struct {
+	message string
+	err     error
+}
+
+func approvePRCmd(c *GitHubClient, pr PullRequest) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.ApprovePR(pr.Number); err != nil {
+			return prReviewedMsg{err: err}
+		}
+		return prReviewedMsg{message: fmt.Sprintf("Approved PR #%d", pr.Number)}
+	}
+}
+
+func commentOnPRCmd(c *GitHubClient, pr PullRequest, body string) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.CommentOnPR(pr.Number, body); err != nil {
+			return prReviewedMsg{err: err}
+		}
+		return prReviewedMsg{message: fmt.Sprintf("Commented on PR #%d", pr.Number)}
+	}
+}
+
+// prCommentPrompt asks for a comment to post on a pull request. Like
+// reviewPrompt it is shown as an overlay and captures all input until
+// submitted or cancelled.
+type prCommentPrompt struct {
+	pr    PullRequest
+	input textinput.Model
+}
+
+func newPRCommentPrompt(pr PullRequest) *prCommentPrompt {
+	ti := textinput.New()
+	ti.Prompt = "comment> "
+	ti.Width = 50
+	ti.Focus()
+	return &prCommentPrompt{pr: pr, input: ti}
+}
+
+// updatePRComment handles input while the comment prompt is open.
+func (m model) updatePRComment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.prComment
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc":
+		m.prComment = nil
+		return m, nil
+	case "enter":
+		body := strings.TrimSpace(p.input.Value())
+		if body == "" {
+			return m, nil
+		}
+		m.prComment = nil
+		m.loading = true
+		m.statusMsg = fmt.Sprintf("Commenting on PR #%d…", p.pr.Number)
+		return m, commentOnPRCmd(m.client, p.pr, body)
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return m, cmd
+}
+
+// viewPRComment renders the comment prompt centred on screen.
+func (m model) viewPRComment() string {
+	p := m.prComment
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAmber).
+		Padding(1, 3).
+		Render(styleHeader.Render(fmt.Sprintf("Comment on #%d %s", p.pr.Number, truncate(p.pr.Title, 50))) + "\n\n" +
+			p.input.View() + "\n\n" +
+			renderFooter([]string{"<enter> post", "<esc> cancel"}))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
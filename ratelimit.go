@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// rateLimit is the REST API quota as of the last response that reported it.
+// GHES instances with rate limiting disabled send no headers and never set it.
+type rateLimit struct {
+	limit, remaining int
+	reset            time.Time // server time the quota is replenished
+}
+
+// exhausted reports whether no requests are left before the reset at now.
+func (r rateLimit) exhausted(now time.Time) bool {
+	return r.remaining == 0 && now.Before(r.reset)
+}
+
+// recordRateLimit stores the X-RateLimit-* headers of a response. Only the
+// core quota is tracked; the search and GraphQL quotas are separate.
+func (c *GitHubClient) recordRateLimit(h http.Header) {
+	if res := h.Get("X-RateLimit-Resource"); res != "" && res != "core" {
+		return
+	}
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	c.mu.Lock()
+	c.rate = &rateLimit{limit: limit, remaining: remaining, reset: time.Unix(reset, 0)}
+	c.mu.Unlock()
+}
+
+// RateLimit returns the REST API quota and whether the server reported one.
+func (c *GitHubClient) RateLimit() (rateLimit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rate == nil {
+		return rateLimit{}, false
+	}
+	return *c.rate, true
+}
+
+// RateLimited reports whether the quota is used up until its reset.
+func (c *GitHubClient) RateLimited() bool {
+	r, ok := c.RateLimit()
+	return ok && r.exhausted(c.Now())
+}
+
+// rateLimitMessage describes err when it is a rate-limit rejection: a 403 or
+// 429 with no requests left, or GitHub's secondary (abuse) limit.
+func (c *GitHubClient) rateLimitMessage(err error) (string, bool) {
+	var herr *api.HTTPError
+	if !errors.As(err, &herr) || (herr.StatusCode != http.StatusForbidden && herr.StatusCode != http.StatusTooManyRequests) {
+		return "", false
+	}
+	if strings.Contains(strings.ToLower(herr.Message), "secondary rate limit") {
+		msg := "GitHub secondary rate limit hit"
+		if secs, err := strconv.Atoi(herr.Headers.Get("Retry-After")); err == nil {
+			msg += fmt.Sprintf("; retry in %s", time.Duration(secs)*time.Second)
+		}
+		return msg, true
+	}
+	if herr.Headers.Get("X-RateLimit-Remaining") != "0" {
+		return "", false
+	}
+	msg := "GitHub API rate limit exceeded"
+	if r, ok := c.RateLimit(); ok {
+		if r.limit > 0 {
+			msg = fmt.Sprintf("GitHub API rate limit of %d requests exceeded", r.limit)
+		}
+		msg += fmt.Sprintf("; polling paused until %s", r.reset.Local().Format("15:04"))
+	}
+	return msg, true
+}
+
+// rateLimitIndicator is the app bar's API quota, or "" when the server doesn't
+// report one.
+func (m model) rateLimitIndicator() string {
+	r, ok := m.client.RateLimit()
+	if !ok {
+		return ""
+	}
+	if r.exhausted(m.client.Now()) {
+		return fmt.Sprintf("API: 0 left until %s", r.reset.Local().Format("15:04"))
+	}
+	return fmt.Sprintf("API: %d left", r.remaining)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redactMask replaces each redacted match.
+const redactMask = "***"
+
+// builtinRedactPatterns match common credential formats that GitHub's own
+// secret masking misses when tools print tokens that were never registered as
+// secrets.
+var builtinRedactPatterns = []string{
+	`gh[pousr]_[A-Za-z0-9]{36,}`,                                       // GitHub tokens
+	`github_pat_[A-Za-z0-9_]{22,}`,                                     // GitHub fine-grained tokens
+	`(?:A3T[A-Z0-9]|AKIA|ASIA)[A-Z0-9]{16}`,                            // AWS access key IDs
+	`xox[abprs]-[A-Za-z0-9-]{10,}`,                                     // Slack tokens
+	`eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`,    // JWTs
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----`,                               // PEM private key headers
+	`(?i)(?:bearer|authorization:\s*token)\s+[A-Za-z0-9._~+/-]{20,}=*`, // bearer tokens in headers
+}
+
+// redactConfig is the opt-in redaction of sensitive-looking values in
+// displayed logs.
+type redactConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Patterns []string `yaml:"patterns"` // extra regexes, in addition to the built-in ones
+	Copy     bool     `yaml:"copy"`     // also redact logs copied to the clipboard
+
+	compiled []*regexp.Regexp
+}
+
+// compile prepares the built-in and configured patterns.
+func (r *redactConfig) compile() error {
+	r.compiled = nil
+	for _, p := range append(append([]string{}, builtinRedactPatterns...), r.Patterns...) {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("redact pattern %q: %w", p, err)
+		}
+		r.compiled = append(r.compiled, re)
+	}
+	return nil
+}
+
+// apply masks every match in s. It returns s unchanged when redaction is off.
+func (r redactConfig) apply(s string) string {
+	if !r.Enabled {
+		return s
+	}
+	for _, re := range r.compiled {
+		s = re.ReplaceAllString(s, redactMask)
+	}
+	return s
+}
+
+// applyLines is apply for multi-line text, matching each line on its own as
+// the log pane does, so a pattern can't swallow a line break.
+func (r redactConfig) applyLines(s string) string {
+	if !r.Enabled {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = r.apply(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyCopy is apply for text leaving tgh through the clipboard.
+func (r redactConfig) applyCopy(s string) string {
+	if !r.Copy {
+		return s
+	}
+	return r.apply(s)
+}
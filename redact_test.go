@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactSummaryAndAnnotations(t *testing.T) {
+	token := "ghp_" + strings.Repeat("a", 36)
+	r := redactConfig{Enabled: true}
+	if err := r.compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := renderMarkdown(r.applyLines("# Deploy\n\n- token: `"+token+"`\n"), 80)
+	if strings.Contains(summary, token) || !strings.Contains(summary, redactMask) {
+		t.Errorf("summary not redacted:\n%s", summary)
+	}
+
+	items := annotationItems([]Annotation{{AnnotationLevel: "failure", Title: token, Message: "auth failed for " + token}}, r)
+	a := items[0].(annotationItem).a
+	if strings.Contains(a.Title, token) || strings.Contains(a.Message, token) {
+		t.Errorf("annotation not redacted: %+v", a)
+	}
+	if row := formatAnnotationRow(a, 120, false); strings.Contains(row, token) {
+		t.Errorf("annotation row not redacted: %q", row)
+	}
+
+	// Off, the text is left alone.
+	items = annotationItems([]Annotation{{Message: token}}, redactConfig{})
+	if got := items[0].(annotationItem).a.Message; got != token {
+		t.Errorf("Message = %q with redaction off, want it unchanged", got)
+	}
+}
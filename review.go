@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// reviewPrompt approves or rejects a run's deployments waiting for the
+// current user's review, with an optional comment. Like confirmPrompt it is
+// shown as an overlay and captures all input until submitted or cancelled.
+type reviewPrompt struct {
+	run     WorkflowRun
+	envIDs  []int64
+	envs    string // environment names, comma separated
+	approve bool
+	comment textinput.Model
+}
+
+// newReviewPrompt returns a prompt for the deployments of run the current
+// user may review, or nil when there are none.
+func newReviewPrompt(run WorkflowRun, deployments []PendingDeployment) *reviewPrompt {
+	var ids []int64
+	var names []string
+	for _, d := range deployments {
+		if d.CurrentUserCanApprove {
+			ids = append(ids, d.Environment.ID)
+			names = append(names, d.Environment.Name)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	ti := textinput.New()
+	ti.Prompt = "comment> "
+	ti.Placeholder = "optional"
+	ti.Width = 50
+	ti.Focus()
+	return &reviewPrompt{run: run, envIDs: ids, envs: strings.Join(names, ", "), approve: true, comment: ti}
+}
+
+// updateReview handles input while the review prompt is open.
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.review
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc":
+		m.review = nil
+		return m, nil
+	case "tab", "shift+tab":
+		r.approve = !r.approve
+		return m, nil
+	case "enter":
+		m.review = nil
+		m.loading = true
+		verb := "Rejecting"
+		if r.approve {
+			verb = "Approving"
+		}
+		m.statusMsg = fmt.Sprintf("%s deployment to %s…", verb, r.envs)
+		return m, reviewDeploymentsCmd(m.client, r.run.ID, r.envIDs, r.envs, r.approve, strings.TrimSpace(r.comment.Value()))
+	}
+	var cmd tea.Cmd
+	r.comment, cmd = r.comment.Update(msg)
+	return m, cmd
+}
+
+// viewReview renders the review prompt centred on screen.
+func (m model) viewReview() string {
+	r := m.review
+	approve, reject := " Approve ", " Reject "
+	if r.approve {
+		approve = lipgloss.NewStyle().Background(colorGreen).Foreground(lipgloss.Color("0")).Bold(true).Render(focusMark(approve))
+		reject = styleDim.Render(reject)
+	} else {
+		approve = styleDim.Render(approve)
+		reject = lipgloss.NewStyle().Background(colorRed).Foreground(lipgloss.Color("15")).Bold(true).Render(focusMark(reject))
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAmber).
+		Padding(1, 3).
+		Render(styleHeader.Render(fmt.Sprintf("Review deployment of %s to %s", r.run.Name, r.envs)) + "\n\n" +
+			approve + "  " + reject + "\n\n" +
+			r.comment.View() + "\n\n" +
+			renderFooter([]string{"<tab> approve/reject", "<enter> submit", "<esc> cancel"}))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
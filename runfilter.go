@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runStatusFilter narrows the runs list to runs in one state. It applies to
+// the loaded runs, on top of the / text filter.
+type runStatusFilter int
+
+const (
+	runFilterAll runStatusFilter = iota
+	runFilterFailed
+	runFilterRunning
+	runFilterSuccess
+	numRunFilters
+)
+
+func (f runStatusFilter) String() string {
+	switch f {
+	case runFilterFailed:
+		return "failed"
+	case runFilterRunning:
+		return "in progress"
+	case runFilterSuccess:
+		return "successful"
+	default:
+		return "all"
+	}
+}
+
+// matches reports whether run is shown under the filter.
+func (f runStatusFilter) matches(run WorkflowRun) bool {
+	switch f {
+	case runFilterFailed:
+		return run.Status == "completed" && (run.Conclusion == "failure" || run.Conclusion == "timed_out" || run.Conclusion == "startup_failure")
+	case runFilterRunning:
+		return isUnfinished(run.Status)
+	case runFilterSuccess:
+		return run.Status == "completed" && run.Conclusion == "success"
+	default:
+		return true
+	}
+}
+
+// setRuns stores the loaded runs and shows those matching the status filter,
+// keeping the cursor on the run with selectID when it is still listed.
+func (m *model) setRuns(runs []WorkflowRun, selectID int64) tea.Cmd {
+	m.loadedRuns = runs
+	var items []list.Item
+	for _, r := range runs {
+		if m.runStatusFilter.matches(r) {
+			items = append(items, runItem{run: r, progress: m.runProgress[r.ID]})
+		}
+	}
+	cmd := m.runsList.SetItems(items)
+	// A filtered list is re-filtered asynchronously, so its indices are not
+	// known yet; leave it alone.
+	if m.runsList.FilterState() != list.Unfiltered {
+		return cmd
+	}
+	for i, item := range items {
+		if item.(runItem).run.ID == selectID {
+			m.runsList.Select(i)
+			return cmd
+		}
+	}
+	if m.runsList.Index() >= len(items) {
+		m.runsList.Select(max(0, len(items)-1))
+	}
+	return cmd
+}
+
+// cycleRunFilter switches to the next status filter: all, failed, in
+// progress, successful.
+func (m *model) cycleRunFilter() tea.Cmd {
+	m.runStatusFilter = (m.runStatusFilter + 1) % numRunFilters
+	var selectedID int64
+	if item, ok := m.runsList.SelectedItem().(runItem); ok {
+		selectedID = item.run.ID
+	}
+	return m.setRuns(m.loadedRuns, selectedID)
+}
+
+// runFilterLabel is the breadcrumb suffix naming the active status filter.
+func (m model) runFilterLabel() string {
+	if m.runStatusFilter == runFilterAll {
+		return ""
+	}
+	return " (" + m.runStatusFilter.String() + " only)"
+}
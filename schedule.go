@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A scheduled run only records the event "schedule", not which of the
+// workflow's cron entries fired. likelySchedule recovers it by matching the
+// entries against the run's creation time. GitHub evaluates schedules in UTC
+// and often starts runs late under load, so the most recent fire time before
+// the run wins.
+
+// maxScheduleDelay bounds how late a scheduled run may start and still be
+// attributed to a cron entry.
+const maxScheduleDelay = 3 * time.Hour
+
+// scheduleMatch is the cron entry that most likely triggered a run.
+type scheduleMatch struct {
+	Cron  string
+	Fired time.Time // when the entry was due, zero if no fire time matched
+	Count int       // cron entries in the workflow
+}
+
+// parseWorkflowSchedules returns the cron expressions of a workflow's
+// on.schedule block.
+func parseWorkflowSchedules(data []byte) ([]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	scheduleNode := findMappingValue(findMappingValue(doc.Content[0], "on"), "schedule")
+	if scheduleNode == nil || scheduleNode.Kind != yaml.SequenceNode {
+		return nil, nil
+	}
+	var crons []string
+	for _, entry := range scheduleNode.Content {
+		if cron := findMappingValue(entry, "cron"); cron != nil && cron.Value != "" {
+			crons = append(crons, cron.Value)
+		}
+	}
+	return crons, nil
+}
+
+// likelySchedule picks the cron entry whose last fire time at or before
+// created is the most recent. A workflow with a single entry always matches
+// it, with Fired left zero when no fire time is close enough.
+func likelySchedule(crons []string, created time.Time) (scheduleMatch, bool) {
+	best := scheduleMatch{Count: len(crons)}
+	for _, expr := range crons {
+		spec, err := parseCron(expr)
+		if err != nil {
+			dbg("ignoring schedule %q: %v", expr, err)
+			continue
+		}
+		if fired, ok := spec.lastFire(created); ok && fired.After(best.Fired) {
+			best.Cron, best.Fired = expr, fired
+		}
+	}
+	if best.Cron == "" && len(crons) == 1 {
+		best.Cron = crons[0]
+	}
+	return best, best.Cron != ""
+}
+
+// cronSpec is a parsed five-field cron expression; each field is a bit set of
+// the values it allows.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var (
+	cronMonths = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	cronDays = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+// parseCron parses the POSIX cron syntax GitHub accepts: lists, ranges,
+// steps and month/day names.
+func parseCron(expr string) (cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("want 5 fields, got %d", len(fields))
+	}
+	var s cronSpec
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return s, err
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return s, err
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return s, err
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return s, err
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7, cronDays); err != nil {
+		return s, err
+	}
+	if s.dow&(1<<7) != 0 { // 7 is Sunday too
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return s, nil
+}
+
+func parseCronField(field string, lo, hi int, names map[string]int) (uint64, error) {
+	value := func(v string) (int, error) {
+		if n, ok := names[strings.ToUpper(v)]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < lo || n > hi {
+			return 0, fmt.Errorf("bad value %q", v)
+		}
+		return n, nil
+	}
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step %q", stepStr)
+			}
+			step = n
+		}
+		start, end := lo, hi
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = value(from); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = value(to); err != nil {
+					return 0, err
+				}
+				if start > end {
+					return 0, fmt.Errorf("reversed range %q", rng)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// matches reports whether the spec fires at minute t (UTC). As in cron, a
+// restricted day of month and day of week match if either does.
+func (s cronSpec) matches(t time.Time) bool {
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domOK := s.dom&(1<<t.Day()) != 0
+	dowOK := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// lastFire returns the latest fire time at or before t, looking back at most
+// maxScheduleDelay.
+func (s cronSpec) lastFire(t time.Time) (time.Time, bool) {
+	t = t.UTC().Truncate(time.Minute)
+	for back := time.Duration(0); back <= maxScheduleDelay; back += time.Minute {
+		if at := t.Add(-back); s.matches(at) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
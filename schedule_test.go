@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func cronBits(values ...int) uint64 {
+	var bits uint64
+	for _, v := range values {
+		bits |= 1 << v
+	}
+	return bits
+}
+
+func cronRange(from, to, step int) uint64 {
+	var bits uint64
+	for v := from; v <= to; v += step {
+		bits |= 1 << v
+	}
+	return bits
+}
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		field  string
+		lo, hi int
+		names  map[string]int
+		want   uint64
+	}{
+		{"*", 0, 59, nil, cronRange(0, 59, 1)},
+		{"5", 0, 59, nil, cronBits(5)},
+		{"1,15,30", 0, 59, nil, cronBits(1, 15, 30)},
+		{"9-17", 0, 23, nil, cronRange(9, 17, 1)},
+		{"*/15", 0, 59, nil, cronBits(0, 15, 30, 45)},
+		{"10-30/10", 0, 59, nil, cronBits(10, 20, 30)},
+		{"5/20", 0, 59, nil, cronBits(5, 25, 45)},
+		{"JAN,jul", 1, 12, cronMonths, cronBits(1, 7)},
+		{"MON-FRI", 0, 7, cronDays, cronRange(1, 5, 1)},
+		{"*/2", 1, 12, cronMonths, cronBits(1, 3, 5, 7, 9, 11)},
+	}
+	for _, tt := range tests {
+		got, err := parseCronField(tt.field, tt.lo, tt.hi, tt.names)
+		if err != nil || got != tt.want {
+			t.Errorf("parseCronField(%q) = %b, %v; want %b", tt.field, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"0 0 * *",         // too few fields
+		"0 0 * * * *",     // too many fields
+		"60 * * * *",      // minute out of range
+		"* 24 * * *",      // hour out of range
+		"* * 0 * *",       // day of month out of range
+		"* * * 13 *",      // month out of range
+		"* * * * 8",       // day of week out of range
+		"*/0 * * * *",     // zero step
+		"*/x * * * *",     // bad step
+		"1- * * * *",      // open range
+		"30-10 * * * *",   // reversed range
+		"* * * * FRI-MON", // reversed range of names
+		"* * * FOO *",     // unknown name
+		"* * * * MON-X",   // unknown name in a range
+	} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// 2024-03-15 is a Friday, 2024-03-17 a Sunday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		expr string
+		at   time.Time
+		want bool
+	}{
+		{"30 9 * * *", at(15, 9, 30), true},
+		{"30 9 * * *", at(15, 9, 31), false},
+		{"0 */6 * * *", at(15, 18, 0), true},
+		{"0 */6 * * *", at(15, 19, 0), false},
+		{"0 9 * * MON-FRI", at(15, 9, 0), true},
+		{"0 9 * * MON-FRI", at(17, 9, 0), false},
+		{"0 9 * * 7", at(17, 9, 0), true}, // 7 is Sunday too
+		{"0 9 * MAR *", at(15, 9, 0), true},
+		{"0 9 * APR *", at(15, 9, 0), false},
+		// Day of month and day of week both restricted: either matches.
+		{"0 9 1 * FRI", at(15, 9, 0), true},
+		{"0 9 15 * MON", at(15, 9, 0), true},
+		{"0 9 1 * MON", at(15, 9, 0), false},
+		// Only one restricted: it must match.
+		{"0 9 1 * *", at(15, 9, 0), false},
+		{"0 9 * * MON", at(15, 9, 0), false},
+	}
+	for _, tt := range tests {
+		spec, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := spec.matches(tt.at); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.expr, tt.at.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestLikelySchedule(t *testing.T) {
+	created := time.Date(2024, time.March, 15, 9, 12, 40, 0, time.UTC)
+	tests := []struct {
+		name      string
+		crons     []string
+		wantCron  string
+		wantFired time.Time
+		wantOK    bool
+	}{
+		{"most recent fire wins", []string{"0 6 * * *", "0 9 * * *"}, "0 9 * * *", time.Date(2024, time.March, 15, 9, 0, 0, 0, time.UTC), true},
+		{"started late", []string{"0 7 * * *"}, "0 7 * * *", time.Date(2024, time.March, 15, 7, 0, 0, 0, time.UTC), true},
+		{"single entry without a close fire", []string{"0 0 1 1 *"}, "0 0 1 1 *", time.Time{}, true},
+		{"none close among several", []string{"0 0 1 1 *", "0 0 1 2 *"}, "", time.Time{}, false},
+		{"invalid entry skipped", []string{"bad", "*/5 * * * *"}, "*/5 * * * *", time.Date(2024, time.March, 15, 9, 10, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		got, ok := likelySchedule(tt.crons, created)
+		if ok != tt.wantOK || got.Cron != tt.wantCron || !got.Fired.Equal(tt.wantFired) || got.Count != len(tt.crons) {
+			t.Errorf("%s: likelySchedule = %+v, %v; want %q fired %s", tt.name, got, ok, tt.wantCron, tt.wantFired)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// session is the view the user was in when tgh last exited, restored on the
+// next start when restore_session is enabled in the config.
+type session struct {
+	Repo  string `yaml:"repo"`             // host/owner/repo
+	View  string `yaml:"view"`             // "runs", "jobs", "logs" or "prs"
+	PR    int    `yaml:"pr,omitempty"`     // PR number selected in the PR list
+	RunID int64  `yaml:"run_id,omitempty"` // run whose jobs (or logs) were open
+	JobID int64  `yaml:"job_id,omitempty"` // job whose logs were open
+}
+
+func sessionPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "session.yml"), nil
+}
+
+// loadSession returns the last saved session, or nil if there is none.
+func loadSession() *session {
+	path, err := sessionPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var s session
+	if err := yaml.Unmarshal(data, &s); err != nil || s.Repo == "" {
+		dbg("ignoring session file %s: %v", path, err)
+		return nil
+	}
+	return &s
+}
+
+func saveSession(s session) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// sessionSnapshot captures the current view for saving on exit. PR-scoped
+// runs, jobs and logs are saved as the PR list with that PR selected.
+func (m model) sessionSnapshot() session {
+	s := session{Repo: m.client.host + "/" + m.client.owner + "/" + m.client.repo}
+	switch {
+	case m.selectedPR != nil && (m.state == stateRuns || m.state == stateJobs || m.state == stateLogs):
+		s.View, s.PR = "prs", m.selectedPR.Number
+	case m.state == statePRs:
+		s.View = "prs"
+		if item, ok := m.prsList.SelectedItem().(prItem); ok {
+			s.PR = item.pr.Number
+		}
+	case m.state == stateJobs && m.prJobsRuns == nil:
+		s.View, s.RunID = "jobs", m.selectedRun.ID
+	case m.state == stateLogs && m.prJobsRuns == nil:
+		s.View, s.RunID, s.JobID = "logs", m.selectedRun.ID, m.selectedJob.ID
+	case m.state == stateRuns, m.state == stateJobs, m.state == stateLogs:
+		s.View = "runs"
+	}
+	return s
+}
+
+// beginRestore puts the model into the list view the session starts from;
+// Init fetches it. Deeper levels (run, job) are replayed as the lists load, see
+// restoreRun and restoreJob. Unknown views start at the menu.
+func (m *model) beginRestore(s *session) {
+	switch s.View {
+	case "runs", "jobs", "logs":
+		m.restore = s
+		m.state = stateRuns
+		m.loading = true
+		m.runsPolling = true
+	case "prs":
+		m.restore = s
+		m.state = statePRs
+		m.loading = true
+	}
+}
+
+// restoreRun opens the session's run once the runs list has loaded, falling
+// back to the list when the run is no longer among the recent runs.
+func (m *model) restoreRun() tea.Cmd {
+	s := m.restore
+	if s.RunID == 0 {
+		m.restore = nil
+		return nil
+	}
+	for i, item := range m.runsList.Items() {
+		if ri, ok := item.(runItem); ok && ri.run.ID == s.RunID {
+			m.runsList.Select(i)
+			if s.JobID == 0 {
+				m.restore = nil
+			}
+			return m.openRun(ri.run)
+		}
+	}
+	m.restore = nil
+	return nil
+}
+
+// restoreJob opens the session's job once the jobs list has loaded.
+func (m *model) restoreJob(jobs []Job) tea.Cmd {
+	jobID := m.restore.JobID
+	m.restore = nil
+	for i, j := range jobs {
+		if j.ID == jobID {
+			m.jobsList.Select(i)
+			return m.openLogs(j)
+		}
+	}
+	return nil
+}
+
+// deepLinkMsg carries the run named by the --run/--job launch flags.
+type deepLinkMsg struct {
+	run   WorkflowRun
+	jobID int64
+	err   error
+}
+
+// fetchDeepLinkCmd resolves the --run/--job target. A job is looked up first
+// to find its run.
+func fetchDeepLinkCmd(c *GitHubClient, runID, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		if jobID != 0 {
+			job, err := c.GetJob(jobID)
+			if err != nil {
+				return deepLinkMsg{err: fmt.Errorf("job %d: %w", jobID, err)}
+			}
+			runID = job.RunID
+		}
+		run, err := c.GetRun(runID)
+		if err != nil {
+			return deepLinkMsg{err: fmt.Errorf("run %d: %w", runID, err)}
+		}
+		return deepLinkMsg{run: run, jobID: jobID}
+	}
+}
+
+// beginDeepLink starts on the runs list and opens the given run or job once it
+// has been fetched. Unlike a restored session, the run need not be recent.
+func (m *model) beginDeepLink(runID, jobID int64) {
+	m.deepLink = &session{RunID: runID, JobID: jobID}
+	m.state = stateRuns
+	m.loading = true
+	m.runsPolling = true
+}
+
+// deepLinkCommand returns a tgh invocation that opens the current run or job,
+// for sharing with someone who has tgh installed. Empty outside those views.
+func (m model) deepLinkCommand() string {
+	repo := m.client.owner + "/" + m.client.repo
+	if m.client.host != "github.com" {
+		repo = m.client.host + "/" + repo
+	}
+	switch m.state {
+	case stateRuns:
+		if item, ok := m.runsList.SelectedItem().(runItem); ok {
+			return fmt.Sprintf("tgh --repo %s --run %d", repo, item.run.ID)
+		}
+	case stateJobs:
+		if item, ok := m.jobsList.SelectedItem().(jobItem); ok {
+			return fmt.Sprintf("tgh --repo %s --job %d", repo, item.job.ID)
+		}
+	case stateLogs:
+		return fmt.Sprintf("tgh --repo %s --job %d", repo, m.selectedJob.ID)
+	}
+	return ""
+}
+
+// restorePR selects the session's PR once the PR list has loaded.
+func (m *model) restorePR() {
+	number := m.restore.PR
+	m.restore = nil
+	for i, item := range m.prsList.Items() {
+		if pi, ok := item.(prItem); ok && pi.pr.Number == number {
+			m.prsList.Select(i)
+			return
+		}
+	}
+}
@@ -2,8 +2,9 @@ package main
 
 import "github.com/charmbracelet/lipgloss"
 
-// ANSI 256-color palette
-const (
+// ANSI 256-color palette. The theme section of the config file can override
+// these; see useTheme.
+var (
 	colorCyan     = lipgloss.Color("51")
 	colorAmber    = lipgloss.Color("214")
 	colorGreen    = lipgloss.Color("76")
@@ -13,90 +14,113 @@ const (
 	colorDimText  = lipgloss.Color("245")
 	colorWhite    = lipgloss.Color("15")
 	colorYellow   = lipgloss.Color("226")
-	colorHeaderBg = lipgloss.Color("24")  // dark cyan bg for top bar
-	colorSelected = lipgloss.Color("63")  // cornflower blue — visible on dark bg
+	colorHeaderBg = lipgloss.Color("24") // dark cyan bg for top bar
+	colorSelected = lipgloss.Color("63") // cornflower blue — visible on dark bg
 )
 
 var (
+	headerBarStyle, appNameStyle                                 lipgloss.Style
+	breadcrumbStyle, breadcrumbDimStyle, colHeaderStyle          lipgloss.Style
+	normalItemStyle, selectedItemStyle, footerStyle, keyStyle    lipgloss.Style
+	styleAccent, styleError, styleWarn, styleCmd, styleDim       lipgloss.Style
+	styleHeader, styleMatch, styleGroupCursor, filterBarStyle    lipgloss.Style
+	statusInProgress, statusSuccess, statusFailure, statusQueued lipgloss.Style
+	statusNeutral, mdHeading1, mdHeading, mdCodeText             lipgloss.Style
+)
+
+func init() { buildStyles() }
+
+// buildStyles derives the styles from the palette. It runs again when a theme
+// changes the palette.
+func buildStyles() {
 	// Header bar (k9s-style top bar)
 	headerBarStyle = lipgloss.NewStyle().
-			Background(colorHeaderBg).
-			Foreground(colorWhite)
+		Background(colorHeaderBg).
+		Foreground(colorWhite)
 
 	appNameStyle = lipgloss.NewStyle().
-			Background(colorCyan).
-			Foreground(lipgloss.Color("0")).
-			Bold(true).
-			Padding(0, 1)
+		Background(colorCyan).
+		Foreground(lipgloss.Color("0")).
+		Bold(true).
+		Padding(0, 1)
 
 	// Breadcrumb / view title line
 	breadcrumbStyle = lipgloss.NewStyle().
-			Foreground(colorCyan).
-			Bold(true)
+		Foreground(colorCyan).
+		Bold(true)
 
 	breadcrumbDimStyle = lipgloss.NewStyle().
-				Foreground(colorDimText)
+		Foreground(colorDimText)
 
 	// Column header row
 	colHeaderStyle = lipgloss.NewStyle().
-			Foreground(colorDimText).
-			Bold(true)
+		Foreground(colorDimText).
+		Bold(true)
 
 	// List item styles
 	normalItemStyle = lipgloss.NewStyle().
-			Foreground(colorWhite)
+		Foreground(colorWhite)
 
 	selectedItemStyle = lipgloss.NewStyle().
-				Background(colorSelected).
-				Foreground(colorWhite).
-				Bold(true).
-				Width(0) // Will be updated dynamically
+		Background(colorSelected).
+		Foreground(colorWhite).
+		Bold(true).
+		Width(0) // Will be updated dynamically
 
 	// Footer key hints
 	footerStyle = lipgloss.NewStyle().
-			Foreground(colorGray)
+		Foreground(colorGray)
 
 	keyStyle = lipgloss.NewStyle().
-			Foreground(colorCyan).
-			Bold(true)
+		Foreground(colorCyan).
+		Bold(true)
 
 	// Log rendering
 	styleAccent = lipgloss.NewStyle().Foreground(colorCyan)
-	styleError  = lipgloss.NewStyle().Foreground(colorRed)
-	styleWarn   = lipgloss.NewStyle().Foreground(colorYellow)
-	styleCmd    = lipgloss.NewStyle().Foreground(colorGray)
-	styleDim    = lipgloss.NewStyle().Foreground(colorGray)
+	styleError = lipgloss.NewStyle().Foreground(colorRed)
+	styleWarn = lipgloss.NewStyle().Foreground(colorYellow)
+	styleCmd = lipgloss.NewStyle().Foreground(colorGray)
+	styleDim = lipgloss.NewStyle().Foreground(colorGray)
 	styleHeader = lipgloss.NewStyle().Foreground(colorWhite).Bold(true)
+	styleMatch = lipgloss.NewStyle().Background(colorAmber).Foreground(lipgloss.Color("0"))
+
+	// Log group header selected for folding
+	styleGroupCursor = lipgloss.NewStyle().Background(colorSelected).Foreground(colorWhite)
 
 	// Filter bar (log search)
 	filterBarStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("236")).
-			Foreground(colorCyan)
+		Background(lipgloss.Color("236")).
+		Foreground(colorCyan)
 
 	// Status badge styles
 	statusInProgress = lipgloss.NewStyle().Foreground(colorAmber)
-	statusSuccess    = lipgloss.NewStyle().Foreground(colorGreen)
-	statusFailure    = lipgloss.NewStyle().Foreground(colorRed)
-	statusQueued     = lipgloss.NewStyle().Foreground(colorBlue)
-	statusNeutral    = lipgloss.NewStyle().Foreground(colorGray)
-)
+	statusSuccess = lipgloss.NewStyle().Foreground(colorGreen)
+	statusFailure = lipgloss.NewStyle().Foreground(colorRed)
+	statusQueued = lipgloss.NewStyle().Foreground(colorBlue)
+	statusNeutral = lipgloss.NewStyle().Foreground(colorGray)
+
+	// Markdown (job summaries)
+	mdHeading1 = lipgloss.NewStyle().Foreground(colorCyan).Bold(true).Underline(true)
+	mdHeading = lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
+	mdCodeText = lipgloss.NewStyle().Foreground(colorAmber)
+}
 
 func statusIcon(status, conclusion string) string {
 	switch {
 	case status == "in_progress":
-		return statusInProgress.Render("●")
+		return statusInProgress.Render(icons.running)
 	case conclusion == "success":
-		return statusSuccess.Render("✓")
+		return statusSuccess.Render(icons.success)
 	case conclusion == "failure":
-		return statusFailure.Render("✗")
+		return statusFailure.Render(icons.failure)
 	case status == "queued":
-		return statusQueued.Render("○")
+		return statusQueued.Render(icons.queued)
 	case conclusion == "cancelled":
-		return statusNeutral.Render("⊘")
+		return statusNeutral.Render(icons.cancelled)
 	case conclusion == "skipped":
-		return statusNeutral.Render("–")
+		return statusNeutral.Render(icons.skipped)
 	default:
-		return styleDim.Render("○")
+		return styleDim.Render(icons.pending)
 	}
 }
 
@@ -104,19 +128,19 @@ func statusIcon(status, conclusion string) string {
 func getPlainStatusIcon(status, conclusion string) string {
 	switch {
 	case status == "in_progress":
-		return "●"
+		return icons.running
 	case conclusion == "success":
-		return "✓"
+		return icons.success
 	case conclusion == "failure":
-		return "✗"
+		return icons.failure
 	case status == "queued":
-		return "○"
+		return icons.queued
 	case conclusion == "cancelled":
-		return "⊘"
+		return icons.cancelled
 	case conclusion == "skipped":
-		return "–"
+		return icons.skipped
 	default:
-		return "○"
+		return icons.pending
 	}
 }
 
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Job summaries are GitHub-flavoured markdown written by steps to
+// $GITHUB_STEP_SUMMARY. renderMarkdown covers the subset that summaries use in
+// practice: headings, lists, tables, code blocks, quotes and inline emphasis.
+// Anything else is shown as plain text.
+
+var (
+	mdBold       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdCode       = regexp.MustCompile("`([^`]+)`")
+	mdLink       = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)]*)\)`)
+	mdHTMLTag    = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdOrdered    = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	mdTableDelim = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+
+	mdBoldText = lipgloss.NewStyle().Bold(true)
+)
+
+// renderMarkdown renders markdown for the terminal, wrapping nothing; lines
+// wider than width are cut by the viewport.
+func renderMarkdown(src string, width int) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var out []string
+	inCode := false
+	var table [][]string
+
+	flushTable := func() {
+		if len(table) > 0 {
+			out = append(out, renderMarkdownTable(table, width)...)
+			table = nil
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			flushTable()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, "  "+styleDim.Render(sanitizeLogLine(line)))
+			continue
+		}
+		if strings.HasPrefix(trimmed, "|") {
+			if !mdTableDelim.MatchString(trimmed) {
+				table = append(table, splitTableRow(trimmed))
+			}
+			continue
+		}
+		flushTable()
+
+		switch {
+		case strings.HasPrefix(trimmed, "# "):
+			out = append(out, mdHeading1.Render(mdInline(trimmed[2:])))
+		case strings.HasPrefix(trimmed, "#"):
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out = append(out, mdHeading.Render(mdInline(text)))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "), strings.HasPrefix(trimmed, "+ "):
+			indent := strings.Repeat(" ", len(line)-len(strings.TrimLeft(line, " ")))
+			out = append(out, indent+styleAccent.Render("•")+" "+mdInline(trimmed[2:]))
+		case mdOrdered.MatchString(line):
+			p := mdOrdered.FindStringSubmatch(line)
+			out = append(out, p[1]+styleAccent.Render(p[2]+".")+" "+mdInline(p[3]))
+		case strings.HasPrefix(trimmed, ">"):
+			out = append(out, styleDim.Render("│ ")+mdInline(strings.TrimSpace(trimmed[1:])))
+		case trimmed == "---" || trimmed == "***":
+			out = append(out, styleDim.Render(strings.Repeat("─", max(1, min(width, 60)))))
+		default:
+			out = append(out, mdInline(line))
+		}
+	}
+	flushTable()
+	return strings.Join(out, "\n")
+}
+
+// mdInline strips HTML tags and renders bold, code spans and links.
+func mdInline(s string) string {
+	s = sanitizeLogLine(mdHTMLTag.ReplaceAllString(s, ""))
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdCode.ReplaceAllStringFunc(s, func(m string) string {
+		return mdCodeText.Render(strings.Trim(m, "`"))
+	})
+	return mdBold.ReplaceAllStringFunc(s, func(m string) string {
+		return mdBoldText.Render(strings.Trim(m, "*_"))
+	})
+}
+
+// mdPlain strips markup, leaving the text inline styling would show. Table
+// cells use it so they can be measured and cut.
+func mdPlain(s string) string {
+	s = sanitizeLogLine(mdHTMLTag.ReplaceAllString(s, ""))
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdCode.ReplaceAllString(s, "$1")
+	return mdBold.ReplaceAllString(s, "$1$2")
+}
+
+func splitTableRow(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i, c := range cells {
+		cells[i] = mdPlain(strings.TrimSpace(c))
+	}
+	return cells
+}
+
+// renderMarkdownTable aligns table cells into columns; the first row is the
+// header. Columns are narrowed evenly when the table is wider than width.
+func renderMarkdownTable(rows [][]string, width int) []string {
+	cols := 0
+	for _, r := range rows {
+		cols = max(cols, len(r))
+	}
+	widths := make([]int, cols)
+	for _, r := range rows {
+		for i, c := range r {
+			widths[i] = max(widths[i], lipgloss.Width(c))
+		}
+	}
+	total := 2 + 3*(cols-1)
+	for _, w := range widths {
+		total += w
+	}
+	if over := total - width; over > 0 && cols > 0 {
+		for i := range widths {
+			widths[i] = max(3, widths[i]-over/cols-1)
+		}
+	}
+
+	var out []string
+	for ri, r := range rows {
+		cells := make([]string, cols)
+		for i := range cells {
+			c := ""
+			if i < len(r) {
+				c = r[i]
+			}
+			cells[i] = padRight(truncate(c, widths[i]), widths[i])
+		}
+		if ri == 0 {
+			out = append(out, mdBoldText.Render("  "+strings.Join(cells, " │ ")))
+			seps := make([]string, cols)
+			for i, w := range widths {
+				seps[i] = strings.Repeat("─", w)
+			}
+			out = append(out, styleDim.Render("  "+strings.Join(seps, "─┼─")))
+			continue
+		}
+		out = append(out, "  "+strings.Join(cells, styleDim.Render(" │ ")))
+	}
+	return out
+}
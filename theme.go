@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// themeConfig overrides colors of the default palette. Each value is an ANSI
+// 256-color number ("63") or a hex RGB color ("#5f5fff"); unset keeps the
+// default.
+type themeConfig struct {
+	HeaderBg string `yaml:"header_bg"` // app bar background
+	Accent   string `yaml:"accent"`    // app name, breadcrumbs, key hints
+	Selected string `yaml:"selected"`  // background of the selected row
+	Text     string `yaml:"text"`
+	Dim      string `yaml:"dim"`   // secondary text, skipped and cancelled runs
+	Muted    string `yaml:"muted"` // breadcrumb path, column headers, menu descriptions
+	Success  string `yaml:"success"`
+	Failure  string `yaml:"failure"` // failed runs and error lines
+	Running  string `yaml:"running"` // in-progress runs and search matches
+	Queued   string `yaml:"queued"`
+	Warning  string `yaml:"warning"`
+}
+
+// themeEntry pairs a theme key and its configured value with the palette
+// color it overrides.
+type themeEntry struct {
+	key, value string
+	color      *lipgloss.Color
+}
+
+func (t themeConfig) entries() []themeEntry {
+	return []themeEntry{
+		{"header_bg", t.HeaderBg, &colorHeaderBg},
+		{"accent", t.Accent, &colorCyan},
+		{"selected", t.Selected, &colorSelected},
+		{"text", t.Text, &colorWhite},
+		{"dim", t.Dim, &colorGray},
+		{"muted", t.Muted, &colorDimText},
+		{"success", t.Success, &colorGreen},
+		{"failure", t.Failure, &colorRed},
+		{"running", t.Running, &colorAmber},
+		{"queued", t.Queued, &colorBlue},
+		{"warning", t.Warning, &colorYellow},
+	}
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// validate rejects values lipgloss would silently render without color.
+func (t themeConfig) validate() error {
+	for _, e := range t.entries() {
+		if e.value == "" || hexColor.MatchString(e.value) {
+			continue
+		}
+		if n, err := strconv.Atoi(e.value); err != nil || n < 0 || n > 255 {
+			return fmt.Errorf("theme %s: %q is not a color (want 0-255 or #rrggbb)", e.key, e.value)
+		}
+	}
+	return nil
+}
+
+// useTheme applies the configured colors over the default palette and
+// rebuilds the styles.
+func useTheme(t themeConfig) {
+	for _, e := range t.entries() {
+		if e.value != "" {
+			*e.color = lipgloss.Color(e.value)
+		}
+	}
+	buildStyles()
+}
+
+// noColor is set by useNoColor. Views that show focus by color alone add a
+// text marker then.
+var noColor bool
+
+// noColorRequested reports whether output should be plain: --no-color was
+// given or NO_COLOR is set to a non-empty value (https://no-color.org).
+func noColorRequested(flag bool) bool {
+	return flag || os.Getenv("NO_COLOR") != ""
+}
+
+// useNoColor renders every style as plain text. Logs drop the colors jobs
+// print as well.
+func useNoColor() {
+	noColor = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// focusMark brackets the label of a focused button in no-color mode, where
+// its highlight doesn't show: "  Build  " becomes "[ Build ]".
+func focusMark(label string) string {
+	if !noColor || len(label) < 2 {
+		return label
+	}
+	return "[" + label[1:len(label)-1] + "]"
+}
+
+// matchMark highlights a search hit, bracketing it when there is no color to
+// show it with.
+func matchMark(hit string) string {
+	if noColor {
+		return "[" + hit + "]"
+	}
+	return styleMatch.Render(hit)
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/atotto/clipboard"
@@ -14,31 +19,175 @@ import (
 
 // ─── Message types ────────────────────────────────────────────────────────────
 
-type runsLoadedMsg []WorkflowRun
+// runsLoadedMsg carries the runs of one source of the runs list: the PR head
+// prSHA, the searched branch, or the repository when both are empty.
+type runsLoadedMsg struct {
+	runs   []WorkflowRun
+	prSHA  string
+	branch string
+}
 type jobsLoadedMsg []Job
-type logsLoadedMsg string
+type runProgressMsg struct {
+	runID    int64
+	progress jobProgress
+	err      error
+}
+type checksRollupMsg struct {
+	sha    string
+	rollup ChecksRollup
+}
+type errorAnnotationMsg struct {
+	jobID int64
+	loc   *sourceLocation
+	err   error
+}
+type jobSummaryMsg struct {
+	jobID int64
+	text  string
+	err   error
+}
+
+// runRefreshedMsg carries the current state of a run whose jobs list came
+// back empty.
+type runRefreshedMsg WorkflowRun
+type jobRefreshedMsg struct {
+	jobID int64
+	job   Job
+	err   error
+}
+type logsLoadedMsg struct {
+	jobID       int64
+	completed   bool   // the job had completed when its log was requested
+	content     string // timestamps stripped
+	timestamped string // original log text
+}
 type prsLoadedMsg []PullRequest
 type workflowsLoadedMsg []Workflow
 type workflowInputsMsg []WorkflowInput
 type refOptionsMsg struct {
+	refresh  bool // fetched on request in the open form, not when it opened
 	branches []string
 	tags     []string
 }
+type environmentsMsg []string
+type envVarsMsg struct {
+	env  string
+	vars []EnvVariable
+	err  error
+}
 type dispatchTriggeredMsg string
+type latestRunMsg string
+type redispatchMsg dispatchRecord // confirmed re-dispatch of a history entry
 type defaultBranchMsg string
+type artifactsLoadedMsg struct {
+	runID     int64
+	artifacts []Artifact
+}
+type artifactDownloadedMsg struct {
+	name  string
+	dir   string
+	files int
+	err   error
+}
+type previousJobLogMsg struct {
+	jobID int64 // job being compared
+	run   *WorkflowRun
+	job   *Job
+	log   string // timestamps stripped
+	err   error
+}
+type runScheduleMsg struct {
+	runID    int64
+	schedule *scheduleMatch
+	err      error
+}
+type pendingDeploymentsMsg struct {
+	runID       int64
+	deployments []PendingDeployment
+	err         error
+}
+
+// reviewDeploymentsMsg carries the pending deployments of a run the user
+// asked to review from the runs list.
+type reviewDeploymentsMsg struct {
+	run         WorkflowRun
+	deployments []PendingDeployment
+	err         error
+}
+type cancelRunMsg struct {
+	message string
+	runID   int64
+}
 type rerunMsg struct {
 	message string
 	runID   int64
-	jobID   int64
+	jobID   int64 // the re-run job when a single job was re-run
 }
-type logPollTickMsg struct{}
-type jobsPollTickMsg struct{}
-type runsPollTickMsg struct{}
+type deploymentReviewedMsg struct {
+	approved bool
+	envs     string
+}
+
+// Poll ticks carry the poll generation they were scheduled in; see pollGen.
+type logPollTickMsg struct{ gen int }
+type jobsPollTickMsg struct{ gen int }
+type runsPollTickMsg struct{ gen int }
 type errMsg struct{ err error }
-type pipelineInfoMsg struct{ info *pipelineServiceInfo }
+
+// pollErrMsg reports a failed fetch that the poll loops repeat, which backs
+// off polling; errMsg is for one-off actions.
+type pollErrMsg struct{ err error }
+type clearStatusMsg struct{ seq int }
+
+// Live log messages carry the stream generation they were requested in; see
+// liveGen.
+type pipelineInfoMsg struct {
+	jobID int64
+	gen   int
+	info  *pipelineServiceInfo
+}
 type stepLogsMsg struct {
+	jobID        int64
+	gen          int
 	content      string
 	maxFetchedID int
+	err          error
+}
+type runTimingMsg struct {
+	runID  int64
+	timing *RunTiming
+}
+type liveStrategyMsg struct {
+	jobID    int64
+	gen      int
+	strategy liveLogStrategy
+}
+type liveLogsMsg struct {
+	jobID        int64
+	gen          int
+	content      string
+	nextChangeID int
+	endpointOK   bool
+	err          error
+}
+type blobLogsMsg struct {
+	jobID int64
+	gen   int
+	url   string
+	blob  logRange
+	err   error
+}
+type failingJobMsg struct {
+	pr   PullRequest
+	runs []WorkflowRun
+	run  WorkflowRun
+	jobs []Job
+	job  *Job // nil when no job on the PR's head SHA has failed
+}
+type logPrefetchedMsg struct {
+	jobID   int64
+	content string
+	err     error
 }
 
 // ─── Command helpers ──────────────────────────────────────────────────────────
@@ -47,9 +196,9 @@ func fetchRunsCmd(c *GitHubClient) tea.Cmd {
 	return func() tea.Msg {
 		runs, err := c.ListRuns()
 		if err != nil {
-			return errMsg{err}
+			return pollErrMsg{err}
 		}
-		return runsLoadedMsg(runs)
+		return runsLoadedMsg{runs: runs}
 	}
 }
 
@@ -57,32 +206,315 @@ func fetchRunsForPRCmd(c *GitHubClient, headSHA string) tea.Cmd {
 	return func() tea.Msg {
 		runs, err := c.ListRunsForPR(headSHA)
 		if err != nil {
-			return errMsg{err}
+			return pollErrMsg{err}
+		}
+		return runsLoadedMsg{runs: runs, prSHA: headSHA}
+	}
+}
+
+func fetchRunsForBranchCmd(c *GitHubClient, branch string) tea.Cmd {
+	return func() tea.Msg {
+		runs, err := c.ListRunsForBranch(branch)
+		if err != nil {
+			return pollErrMsg{err}
+		}
+		return runsLoadedMsg{runs: runs, branch: branch}
+	}
+}
+
+// runsCmd fetches the runs the runs list shows: those of the selected PR, of
+// the branch searched from the menu, or the repository's recent runs.
+func (m model) runsCmd() tea.Cmd {
+	switch {
+	case m.selectedPR != nil:
+		return fetchRunsForPRCmd(m.client, m.selectedPR.Head.SHA)
+	case m.branchFilter != "":
+		return fetchRunsForBranchCmd(m.client, m.branchFilter)
+	}
+	return fetchRunsCmd(m.client)
+}
+
+func fetchChecksRollupCmd(c *GitHubClient, sha string) tea.Cmd {
+	return func() tea.Msg {
+		rollup, err := c.GetChecksRollup(sha)
+		if err != nil {
+			// The rollup is a convenience; keep the runs view free of its errors.
+			dbg("GetChecksRollup %s: %v", sha, err)
+			return nil
+		}
+		return checksRollupMsg{sha: sha, rollup: rollup}
+	}
+}
+
+func fetchRunProgressCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		jobs, err := c.ListJobs(runID)
+		p := jobProgress{total: len(jobs)}
+		for _, j := range jobs {
+			if j.Status == "completed" {
+				p.done++
+			}
+		}
+		return runProgressMsg{runID: runID, progress: p, err: err}
+	}
+}
+
+// maxProgressFetches bounds the job-count fetches issued per runs refresh.
+const maxProgressFetches = 6
+
+// runProgressCmds fetches job counts for the in-progress runs on the visible
+// page of the runs list. The runs API has no job counts, so this costs one
+// ListJobs call per run; it is repeated on every runs poll to stay current.
+func (m *model) runProgressCmds() []tea.Cmd {
+	items := m.runsList.VisibleItems()
+	start, end := m.runsList.Paginator.GetSliceBounds(len(items))
+	var cmds []tea.Cmd
+	for _, item := range items[start:end] {
+		ri, ok := item.(runItem)
+		if !ok || !isRunning(ri.run.Status) || m.progressIn[ri.run.ID] {
+			continue
+		}
+		if len(cmds) == maxProgressFetches {
+			break
 		}
-		return runsLoadedMsg(runs)
+		m.progressIn[ri.run.ID] = true
+		cmds = append(cmds, fetchRunProgressCmd(m.client, ri.run.ID))
 	}
+	return cmds
+}
+
+// maxJobFetches bounds concurrent ListJobs calls when listing jobs across runs.
+const maxJobFetches = 4
+
+// fetchPRJobsCmd lists the jobs of every run, in run order, for the aggregated
+// PR jobs view.
+func fetchPRJobsCmd(c *GitHubClient, runs []WorkflowRun) tea.Cmd {
+	return func() tea.Msg {
+		results := make([][]Job, len(runs))
+		errs := make([]error, len(runs))
+		sem := make(chan struct{}, maxJobFetches)
+		var wg sync.WaitGroup
+		for i, r := range runs {
+			wg.Add(1)
+			go func(i int, runID int64) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				results[i], errs[i] = c.ListJobs(runID)
+			}(i, r.ID)
+		}
+		wg.Wait()
+
+		var all []Job
+		for i := range runs {
+			if errs[i] != nil {
+				return pollErrMsg{errs[i]}
+			}
+			all = append(all, results[i]...)
+		}
+		return jobsLoadedMsg(all)
+	}
+}
+
+// jobsCmd refreshes the jobs list: the selected run's jobs, or every run's jobs
+// in the aggregated PR view.
+func (m model) jobsCmd() tea.Cmd {
+	if m.prJobsRuns != nil {
+		return fetchPRJobsCmd(m.client, m.prJobsRuns)
+	}
+	return fetchJobsCmd(m.client, m.selectedRun.ID)
+}
+
+// jobsViewRunID is the run that run-level actions in the jobs view apply to:
+// the selected run, or the selected job's run in the aggregated PR view.
+func (m model) jobsViewRunID() int64 {
+	if m.prJobsRuns != nil {
+		if item, ok := m.jobsList.SelectedItem().(jobItem); ok {
+			return item.job.RunID
+		}
+	}
+	return m.selectedRun.ID
+}
+
+// jobsViewRun is the run behind jobsViewRunID.
+func (m model) jobsViewRun() WorkflowRun {
+	id := m.jobsViewRunID()
+	for _, r := range m.prJobsRuns {
+		if r.ID == id {
+			return r
+		}
+	}
+	return m.selectedRun
+}
+
+// jobsViewJobs are the loaded jobs of jobsViewRun.
+func (m model) jobsViewJobs() []Job {
+	jobs := m.lastJobsForRun[m.selectedRun.ID]
+	if m.prJobsRuns == nil {
+		return jobs
+	}
+	id := m.jobsViewRunID()
+	var runJobs []Job
+	for _, j := range jobs {
+		if j.RunID == id {
+			runJobs = append(runJobs, j)
+		}
+	}
+	return runJobs
+}
+
+// runMetricsShown reports whether the run metrics panel is shown. It has no
+// run to measure in the aggregated PR view.
+func (m model) runMetricsShown() bool {
+	return m.showRunMetrics && m.prJobsRuns == nil
+}
+
+// runHasNoJobs reports whether the selected run finished without creating any
+// jobs, e.g. because path filters or a concurrency group skipped it.
+func (m model) runHasNoJobs() bool {
+	jobs, loaded := m.lastJobsForRun[m.selectedRun.ID]
+	return loaded && len(jobs) == 0 && m.prJobsRuns == nil && m.selectedRun.Status == "completed"
+}
+
+// awaitingReview reports whether the selected run is held by an environment's
+// required reviewers, judging by the run or any of its jobs.
+func (m model) awaitingReview(jobs []Job) bool {
+	if m.selectedRun.Status == "waiting" {
+		return true
+	}
+	for _, j := range jobs {
+		if j.Status == "waiting" {
+			return true
+		}
+	}
+	return false
+}
+
+// runCancellable reports whether run can still be cancelled. In the jobs view
+// the run's status may lag behind its jobs, so running jobs count too.
+func (m model) runCancellable(run WorkflowRun) bool {
+	if isRunning(run.Status) {
+		return true
+	}
+	for _, j := range m.lastJobsForRun[run.ID] {
+		if isRunning(j.Status) {
+			return true
+		}
+	}
+	return false
+}
+
+// rerunWindow is how long after its creation GitHub lets a run be re-run.
+const rerunWindow = 30 * 24 * time.Hour
+
+// rerunBlocker explains why run can't be re-run (only its failed jobs when
+// failedOnly is set), or returns "" when it can. In the jobs view the polled
+// jobs of the shown run take precedence over the run's own status and
+// conclusion, which may lag behind there; elsewhere the cached jobs are the
+// stale ones.
+func (m model) rerunBlocker(run WorkflowRun, failedOnly bool) string {
+	var jobs []Job
+	if m.state == stateJobs && run.ID == m.jobsViewRun().ID {
+		jobs = m.jobsViewJobs()
+	}
+	running := run.Status != "completed"
+	failed := run.Conclusion != "success" && run.Conclusion != "skipped"
+	if len(jobs) > 0 {
+		running, failed = false, false
+		for _, j := range jobs {
+			running = running || isRunning(j.Status)
+			failed = failed || j.Conclusion == "failure" || j.Conclusion == "cancelled" || j.Conclusion == "timed_out"
+		}
+	}
+	switch {
+	case running:
+		return "Run is not completed yet; it can be re-run once it finishes"
+	case clockNow().Sub(run.CreatedAt) > rerunWindow:
+		return "Run is older than 30 days and can no longer be re-run"
+	case failedOnly && !failed:
+		return "Run has no failed jobs to re-run"
+	}
+	return ""
+}
+
+// jobRerunBlocker is rerunBlocker for re-running a single job: the job must
+// have finished, and its run must be re-runnable.
+func (m model) jobRerunBlocker(job Job) string {
+	if job.Status != "completed" {
+		return "Job is not completed yet; it can be re-run once it finishes"
+	}
+	run := m.selectedRun
+	for _, r := range m.prJobsRuns {
+		if r.ID == job.RunID {
+			run = r
+		}
+	}
+	return m.rerunBlocker(run, false)
+}
+
+// prJobsRun is the placeholder run selected while the aggregated PR jobs view is shown.
+func prJobsRun(pr *PullRequest) WorkflowRun {
+	return WorkflowRun{Name: fmt.Sprintf("All checks for #%d", pr.Number), HeadSHA: pr.Head.SHA}
 }
 
 func fetchJobsCmd(c *GitHubClient, runID int64) tea.Cmd {
 	return func() tea.Msg {
 		jobs, err := c.ListJobs(runID)
 		if err != nil {
-			return errMsg{err}
+			return pollErrMsg{err}
 		}
 		return jobsLoadedMsg(jobs)
 	}
 }
 
-func fetchLogsCmd(c *GitHubClient, jobID int64) tea.Cmd {
+func fetchJobCmd(c *GitHubClient, jobID int64) tea.Cmd {
 	return func() tea.Msg {
-		logs, err := c.GetJobLogs(jobID)
+		job, err := c.GetJob(jobID)
+		return jobRefreshedMsg{jobID: jobID, job: job, err: err}
+	}
+}
+
+// fetchRunCmd refreshes a run in the background; failures are only logged.
+func fetchRunCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		run, err := c.GetRun(runID)
 		if err != nil {
-			return errMsg{err}
+			dbg("refreshing run %d: %v", runID, err)
+			return nil
+		}
+		return runRefreshedMsg(run)
+	}
+}
+
+func fetchErrorAnnotationCmd(c *GitHubClient, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		loc, err := c.GetErrorAnnotation(jobID)
+		return errorAnnotationMsg{jobID: jobID, loc: loc, err: err}
+	}
+}
+
+func fetchJobSummaryCmd(c *GitHubClient, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		text, err := c.GetJobSummary(jobID)
+		return jobSummaryMsg{jobID: jobID, text: text, err: err}
+	}
+}
+
+func fetchLogsCmd(c *GitHubClient, job Job) tea.Cmd {
+	completed := job.Status == "completed"
+	return func() tea.Msg {
+		logs, err := c.GetJobLogs(job.ID)
+		if err != nil {
+			return pollErrMsg{err}
 		}
-		return logsLoadedMsg(logs)
+		return newLogsLoadedMsg(job.ID, completed, logs)
 	}
 }
 
+func newLogsLoadedMsg(jobID int64, completed bool, timestamped string) logsLoadedMsg {
+	return logsLoadedMsg{jobID: jobID, completed: completed, content: processLogLines(timestamped), timestamped: timestamped}
+}
+
 func fetchPRsCmd(c *GitHubClient) tea.Cmd {
 	return func() tea.Msg {
 		prs, err := c.ListPullRequests()
@@ -93,6 +525,75 @@ func fetchPRsCmd(c *GitHubClient) tea.Cmd {
 	}
 }
 
+// findFailingJobCmd resolves the first failed job across the runs for a PR's
+// head SHA. Runs still in progress are searched too, as their failed jobs are
+// already final.
+func findFailingJobCmd(c *GitHubClient, pr PullRequest) tea.Cmd {
+	return func() tea.Msg {
+		runs, err := c.ListRunsForPR(pr.Head.SHA)
+		if err != nil {
+			return errMsg{err}
+		}
+		for _, run := range runs {
+			if run.Status == "completed" && run.Conclusion != "failure" {
+				continue
+			}
+			jobs, err := c.ListJobs(run.ID)
+			if err != nil {
+				return errMsg{err}
+			}
+			for i, j := range jobs {
+				if j.Conclusion == "failure" {
+					return failingJobMsg{pr: pr, runs: runs, run: run, jobs: jobs, job: &jobs[i]}
+				}
+			}
+		}
+		return failingJobMsg{pr: pr, runs: runs}
+	}
+}
+
+// noFailingJobStatus explains why runs without a failed job aren't all
+// passing, or returns "" when they are.
+func noFailingJobStatus(runs []WorkflowRun) string {
+	if len(runs) == 0 {
+		return "No checks have run on this PR's head commit"
+	}
+	for _, run := range runs {
+		if run.Status != "completed" {
+			return "No failures yet; some checks are still queued or running"
+		}
+	}
+	for _, run := range runs {
+		switch run.Conclusion {
+		case "success", "skipped", "neutral":
+		default:
+			return fmt.Sprintf("%s ended %s without a failed job", run.Name, statusLabel(run.Status, run.Conclusion))
+		}
+	}
+	return ""
+}
+
+func fetchRunTimingCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		timing, err := c.GetRunTiming(runID)
+		if err != nil {
+			dbg("fetchRunTimingCmd: run %d: %v", runID, err)
+		}
+		return runTimingMsg{runID: runID, timing: timing}
+	}
+}
+
+// runTimingCmd fetches timing for the selected run when the metrics panel is
+// shown and it hasn't been fetched yet.
+func (m *model) runTimingCmd() tea.Cmd {
+	if !m.runMetricsShown() || m.runTimingRunID == m.selectedRun.ID {
+		return nil
+	}
+	m.runTimingRunID = m.selectedRun.ID
+	m.runTiming = nil
+	return fetchRunTimingCmd(m.client, m.selectedRun.ID)
+}
+
 func fetchWorkflowsCmd(c *GitHubClient) tea.Cmd {
 	return func() tea.Msg {
 		wfs, err := c.ListWorkflows()
@@ -123,23 +624,122 @@ func fetchWorkflowInputsCmd(c *GitHubClient, workflow Workflow) tea.Cmd {
 	}
 }
 
-func fetchRefOptionsCmd(c *GitHubClient) tea.Cmd {
+func fetchRefOptionsCmd(c *GitHubClient, refresh bool) tea.Cmd {
 	return func() tea.Msg {
-		branches, tags, err := c.ListRefs()
+		branches, tags, err := c.ListRefs(refresh)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refOptionsMsg{branches: branches, tags: tags}
+		return refOptionsMsg{refresh: refresh, branches: branches, tags: tags}
+	}
+}
+
+func fetchEnvironmentsCmd(c *GitHubClient) tea.Cmd {
+	return func() tea.Msg {
+		envs, err := c.ListEnvironments()
+		if err != nil {
+			dbg("listing environments: %v", err)
+			return nil
+		}
+		return environmentsMsg(envs)
+	}
+}
+
+func fetchEnvVarsCmd(c *GitHubClient, env string) tea.Cmd {
+	return func() tea.Msg {
+		vars, err := c.ListEnvironmentVariables(env)
+		return envVarsMsg{env: env, vars: vars, err: err}
 	}
 }
 
-func triggerDispatchCmd(c *GitHubClient, workflowID int64, ref string, inputs map[string]string) tea.Cmd {
+// triggerDispatchCmd dispatches wf and, on success, records the dispatch in
+// the local history.
+func triggerDispatchCmd(c *GitHubClient, wf Workflow, ref string, inputs map[string]string) tea.Cmd {
 	return func() tea.Msg {
-		if err := c.TriggerWorkflowDispatch(workflowID, ref, inputs); err != nil {
+		if err := c.TriggerWorkflowDispatch(wf.ID, ref, inputs); err != nil {
 			return errMsg{err}
 		}
-		return dispatchTriggeredMsg("✓ Workflow dispatched on " + ref)
+		login, err := c.CurrentLogin()
+		if err != nil {
+			dbg("looking up login for dispatch history: %v", err)
+		}
+		rec := dispatchRecord{
+			WorkflowID: wf.ID,
+			Workflow:   wf.Name,
+			Path:       wf.Path,
+			Ref:        ref,
+			Inputs:     inputs,
+			At:         time.Now(),
+			By:         login,
+		}
+		if err := recordDispatch(c.host+"/"+c.owner+"/"+c.repo, rec); err != nil {
+			dbg("recording dispatch: %v", err)
+		}
+		return dispatchTriggeredMsg("Workflow dispatched on " + ref)
+	}
+}
+
+// maxFailedJobNames bounds the failed jobs named in a completion summary.
+const maxFailedJobNames = 3
+
+// anyRunning reports whether any of jobs hasn't finished.
+func anyRunning(jobs []Job) bool {
+	return slices.ContainsFunc(jobs, func(j Job) bool { return isUnfinished(j.Status) })
+}
+
+// jobsByRun groups jobs by the run they belong to.
+func jobsByRun(jobs []Job) map[int64][]Job {
+	byRun := make(map[int64][]Job)
+	for _, j := range jobs {
+		byRun[j.RunID] = append(byRun[j.RunID], j)
+	}
+	return byRun
+}
+
+// failedJobNames lists the failed jobs, e.g. "test (ubuntu), deploy +2 more",
+// or returns "" when none failed.
+func failedJobNames(jobs []Job) string {
+	var failed []string
+	for _, j := range jobs {
+		if j.Conclusion == "failure" || j.Conclusion == "timed_out" {
+			failed = append(failed, j.Name)
+		}
 	}
+	if len(failed) == 0 {
+		return ""
+	}
+	names := strings.Join(failed[:min(len(failed), maxFailedJobNames)], ", ")
+	if n := len(failed) - maxFailedJobNames; n > 0 {
+		names += fmt.Sprintf(" +%d more", n)
+	}
+	return names
+}
+
+// bellCmd rings the terminal bell.
+func bellCmd() tea.Msg {
+	fmt.Fprint(termOutput, "\a")
+	return nil
+}
+
+// termOutput is the program's output. Commands write to it, rather than to
+// os.Stdout, so their writes don't land in the middle of a rendered frame.
+var termOutput = &lockedFile{File: os.Stdout}
+
+// lockedFile serialises writes to a file. It keeps the file's descriptor, so
+// Bubble Tea still recognises the output as a terminal.
+type lockedFile struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (f *lockedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.File.Write(p)
+}
+
+func (f *lockedFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
 }
 
 // isRunning reports whether a job status means the job hasn't finished.
@@ -147,6 +747,16 @@ func isRunning(status string) bool {
 	return status == "in_progress" || status == "queued"
 }
 
+// isUnfinished reports whether a run or job status means it hasn't finished,
+// counting those still waiting for approval, a runner or concurrency slot.
+func isUnfinished(status string) bool {
+	switch status {
+	case "in_progress", "queued", "waiting", "pending", "requested":
+		return true
+	}
+	return false
+}
+
 // countCompletedSteps returns the number of completed steps in a job.
 func countCompletedSteps(steps []Step) int {
 	count := 0
@@ -158,87 +768,661 @@ func countCompletedSteps(steps []Step) int {
 	return count
 }
 
-// applyLogFilter re-renders the log viewport from m.logRaw, applying m.logFilter.
-func (m *model) applyLogFilter() {
-	content := m.logRaw
+// logDisplayText returns the log lines as shown in the viewport: logRaw with the
+// filter, duplicate collapsing and folded groups applied. logRaw itself is left untouched so
+// copying keeps the full output.
+func (m model) logDisplayText() string {
+	lines, _ := m.logDisplayLines()
+	return strings.Join(lines, "\n")
+}
+
+// logDisplayLines returns the displayed log lines along with the index of the
+// logRaw line each one shows.
+func (m model) logDisplayLines() (lines []string, src []int) {
+	lines = strings.Split(m.logRaw, "\n")
+	src = make([]int, len(lines))
+	for i := range src {
+		src[i] = i
+	}
 	if m.logFilter != "" {
 		lower := strings.ToLower(m.logFilter)
 		var filtered []string
-		for _, line := range strings.Split(content, "\n") {
+		var filteredSrc []int
+		for i, line := range lines {
 			if strings.Contains(strings.ToLower(line), lower) {
 				filtered = append(filtered, line)
+				filteredSrc = append(filteredSrc, src[i])
 			}
 		}
-		content = strings.Join(filtered, "\n")
+		lines, src = filtered, filteredSrc
 	}
-	rendered := renderLogs(content)
-	m.logViewport.SetContent(rendered)
-	m.logContent = rendered
-	if m.autoScroll {
-		m.logViewport.GotoBottom()
+	if m.collapseDupes {
+		lines, src = collapseRepeats(lines, src)
 	}
+	if m.logFilter == "" {
+		lines, src = foldGroups(lines, src, m.foldedGroups)
+	}
+	return lines, src
 }
 
-func jobsPollCmd() tea.Cmd {
-	return tea.Tick(2*time.Second, func(_ time.Time) tea.Msg {
-		return jobsPollTickMsg{}
-	})
+// collapseRepeats replaces each run of identical consecutive lines with a
+// single line suffixed by its repeat count, e.g. "Retrying… (×12)". src holds
+// each line's logRaw index and is reduced alongside.
+func collapseRepeats(lines []string, src []int) ([]string, []int) {
+	out := make([]string, 0, len(lines))
+	outSrc := make([]int, 0, len(src))
+	for i := 0; i < len(lines); {
+		j := i + 1
+		for j < len(lines) && lines[j] == lines[i] {
+			j++
+		}
+		if n := j - i; n > 1 {
+			out = append(out, fmt.Sprintf("%s (×%d)", lines[i], n))
+		} else {
+			out = append(out, lines[i])
+		}
+		outSrc = append(outSrc, src[i])
+		i = j
+	}
+	return out, outSrc
 }
 
-func runsPollCmd() tea.Cmd {
-	return tea.Tick(10*time.Second, func(_ time.Time) tea.Msg {
-		return runsPollTickMsg{}
-	})
+// renderLogContent renders the displayed log into the viewport without moving
+// it, wrapping long lines at the viewport width when wrapping is on.
+func (m *model) renderLogContent() {
+	lines, src := m.logDisplayLines()
+	// Redact line by line: a pattern matching across a line break would
+	// change the line count and misplace rows, matches and groups.
+	redacted := make([]string, len(lines))
+	for i, line := range lines {
+		redacted[i] = m.config.Redact.apply(line)
+	}
+	display := strings.Join(redacted, "\n")
+	stamps := m.logStamps(src)
+	wrapWidth := 0
+	if m.wrapEnabled {
+		wrapWidth = m.logViewport.Width
+		if stamps != nil {
+			wrapWidth = max(1, wrapWidth-logStampWidth)
+		}
+	}
+	rendered, rows := renderLogs(display, m.plainLogs || noColor, m.logSearch, wrapWidth)
+	if stamps != nil {
+		rendered = prefixLogRows(rendered, rows, stamps)
+	}
+	m.logRows = rows
+	m.groupLines = nil
+	if m.logFilter == "" {
+		m.groupLines = groupHeaders(display)
+	}
+	rendered = m.highlightGroupCursor(rendered)
+	m.logViewport.SetContent(rendered)
+	m.logContent = rendered
+	m.findSearchMatches(display)
 }
 
-func logPollCmd() tea.Cmd {
-	return tea.Tick(3*time.Second, func(_ time.Time) tea.Msg {
-		return logPollTickMsg{}
-	})
+// logRow maps a line of the displayed log to the viewport row it starts on,
+// which differs once wrapped lines take several rows.
+func (m model) logRow(line int) int {
+	if m.logRows == nil || line < 0 {
+		return line
+	}
+	return m.logRows[min(line, len(m.logRows)-1)]
 }
 
-func rerunFailedCmd(c *GitHubClient, runID int64) tea.Cmd {
-	return func() tea.Msg {
-		if err := c.RerunFailedJobs(runID); err != nil {
-			return errMsg{err}
-		}
-		return rerunMsg{message: "Re-run triggered for failed jobs!", runID: runID}
+// logLineAt is the inverse of logRow: the displayed line shown at a row.
+func (m model) logLineAt(row int) int {
+	if m.logRows == nil {
+		return row
+	}
+	i, found := slices.BinarySearch(m.logRows, row)
+	if !found {
+		i--
 	}
+	return max(0, i)
 }
 
-func rerunAllCmd(c *GitHubClient, runID int64) tea.Cmd {
-	return func() tea.Msg {
-		if err := c.RerunAll(runID); err != nil {
-			return errMsg{err}
+// applyLogFilter re-renders the log viewport from m.logRaw, applying m.logFilter.
+// A non-empty filter behaves like a search and shows the first matches at the top;
+// clearing it restores the scroll position from before the filter was applied.
+func (m *model) applyLogFilter() {
+	m.renderLogContent()
+	switch {
+	case m.logFilter != "":
+		if !m.logFiltered {
+			m.logFiltered = true
+			m.preFilterYOff = m.logViewport.YOffset
+			m.preFilterAuto = m.autoScroll
+		}
+		m.autoScroll = false
+		m.logViewport.GotoTop()
+	case m.logFiltered:
+		m.logFiltered = false
+		m.autoScroll = m.preFilterAuto
+		if m.autoScroll {
+			m.logViewport.GotoBottom()
+		} else {
+			m.logViewport.SetYOffset(m.preFilterYOff)
 		}
-		return rerunMsg{message: "Re-run triggered for all jobs!", runID: runID}
+	case m.logPinned:
+		m.logViewport.SetYOffset(m.logRow(m.logAnchor))
+	case m.followStep:
+		m.scrollToActiveStep()
+	case m.autoScroll:
+		m.logViewport.GotoBottom()
 	}
 }
 
-func fetchPipelineInfoCmd(c *GitHubClient, jobID int64) tea.Cmd {
-	return func() tea.Msg {
-		info, err := c.GetPipelineServiceInfo(jobID)
-		if err != nil {
-			dbg("fetchPipelineInfoCmd: %v", err)
-			return pipelineInfoMsg{info: nil}
+// findSearchMatches collects the lines of the displayed log that contain
+// m.logSearch, keeping the current match in range as the log grows.
+func (m *model) findSearchMatches(display string) {
+	m.searchMatches = nil
+	if m.logSearch == "" {
+		m.searchIdx = 0
+		return
+	}
+	lower := strings.ToLower(m.logSearch)
+	for i, line := range strings.Split(display, "\n") {
+		if strings.Contains(strings.ToLower(stripANSI(line)), lower) {
+			m.searchMatches = append(m.searchMatches, i)
 		}
-		return pipelineInfoMsg{info: info}
 	}
+	m.searchIdx = min(m.searchIdx, max(0, len(m.searchMatches)-1))
 }
 
-func fetchStepLogsCmd(info *pipelineServiceInfo, steps []Step, maxFetchedID int) tea.Cmd {
-	return func() tea.Msg {
-		content, newMax, err := FetchNewStepLogs(info, steps, maxFetchedID)
-		if err != nil {
-			return errMsg{err}
+// applyLogSearch highlights m.logSearch and moves to the first match at or
+// below where the search was started, like an incremental search in less.
+func (m *model) applyLogSearch() {
+	m.autoScroll = false
+	m.applyLogFilter()
+	m.searchIdx = 0
+	for i, line := range m.searchMatches {
+		if m.logRow(line) >= m.searchOrigin {
+			m.searchIdx = i
+			break
 		}
-		return stepLogsMsg{content: content, maxFetchedID: newMax}
+	}
+	if len(m.searchMatches) == 0 {
+		m.logViewport.SetYOffset(m.searchOrigin)
+		return
+	}
+	m.scrollToMatch()
+}
+
+// nextMatch moves delta matches forward (or back when negative), wrapping
+// around the ends of the log.
+func (m *model) nextMatch(delta int) {
+	n := len(m.searchMatches)
+	if n == 0 {
+		return
+	}
+	m.searchIdx = ((m.searchIdx+delta)%n + n) % n
+	m.autoScroll = false
+	m.scrollToMatch()
+}
+
+// nextError centers the next ##[error] line of the log in the viewport,
+// cycling through them on repeated calls.
+func (m *model) nextError() {
+	var lines []int
+	for i, line := range strings.Split(m.logDisplayText(), "\n") {
+		if strings.HasPrefix(stripANSI(line), "##[error]") {
+			lines = append(lines, i)
+		}
+	}
+	if len(lines) == 0 {
+		m.statusMsg = "No errors found"
+		return
+	}
+	m.errorIdx = (m.errorIdx + 1) % len(lines)
+	m.followStep = false
+	m.logPinned = false
+	m.autoScroll = false
+	row := m.logRow(lines[m.errorIdx])
+	m.logViewport.SetYOffset(max(0, row-m.logViewport.Height/2))
+	m.statusMsg = fmt.Sprintf("(error %d of %d)", m.errorIdx+1, len(lines))
+}
+
+// scrollToMatch centers the current match in the log viewport.
+func (m *model) scrollToMatch() {
+	row := m.logRow(m.searchMatches[m.searchIdx])
+	m.logViewport.SetYOffset(max(0, row-m.logViewport.Height/2))
+}
+
+// scrollToActiveStep positions the viewport at the log group of the step that
+// is currently executing. The group header is matched by step name, falling
+// back to the most recent group when the names don't line up.
+func (m *model) scrollToActiveStep() {
+	active := ""
+	for _, s := range m.selectedJob.Steps {
+		if s.Status == "in_progress" {
+			active = s.Name
+			break
+		}
+	}
+	match, last := -1, -1
+	for i, line := range strings.Split(m.logDisplayText(), "\n") {
+		if !strings.HasPrefix(line, "##[group]") {
+			continue
+		}
+		last = i
+		if active != "" && strings.Contains(strings.TrimPrefix(line, "##[group]"), active) {
+			match = i
+		}
+	}
+	if match < 0 {
+		match = last
+	}
+	if match >= 0 {
+		m.logViewport.SetYOffset(m.logRow(match))
+	}
+}
+
+// maxLogPrefetch bounds the concurrent background log fetches.
+const maxLogPrefetch = 3
+
+func prefetchLogsCmd(c *GitHubClient, jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		logs, err := c.GetJobLogs(jobID)
+		return logPrefetchedMsg{jobID: jobID, content: logs, err: err}
+	}
+}
+
+// prefetchFailedLogs starts background log fetches for failed jobs that are
+// neither cached nor already in flight, up to maxLogPrefetch at a time.
+func (m *model) prefetchFailedLogs(jobs []Job) []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, j := range jobs {
+		if len(m.logPrefetching) >= maxLogPrefetch {
+			break
+		}
+		if j.Status != "completed" || j.Conclusion != "failure" {
+			continue
+		}
+		if m.logCache.has(j.ID) || m.logPrefetching[j.ID] {
+			continue
+		}
+		m.logPrefetching[j.ID] = true
+		cmds = append(cmds, prefetchLogsCmd(m.client, j.ID))
+	}
+	return cmds
+}
+
+// invalidateRunLogs drops cached logs for all known jobs of a run, whose
+// re-run starts a new attempt.
+func (m *model) invalidateRunLogs(runID int64) {
+	for _, j := range m.lastJobsForRun[runID] {
+		m.logCache.remove(j.ID)
+	}
+}
+
+func (m model) jobsPollCmd() tea.Cmd {
+	gen := m.pollGen
+	return tea.Tick(m.pollDelay(m.poll.jobs), func(_ time.Time) tea.Msg {
+		return jobsPollTickMsg{gen}
+	})
+}
+
+func (m model) runsPollCmd() tea.Cmd {
+	gen := m.pollGen
+	return tea.Tick(m.pollDelay(m.poll.runs), func(_ time.Time) tea.Msg {
+		return runsPollTickMsg{gen}
+	})
+}
+
+func (m model) logPollCmd() tea.Cmd {
+	gen := m.pollGen
+	return tea.Tick(m.pollDelay(m.poll.logs), func(_ time.Time) tea.Msg {
+		return logPollTickMsg{gen}
+	})
+}
+
+func rerunFailedCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.RerunFailedJobs(runID); err != nil {
+			return errMsg{err}
+		}
+		return rerunMsg{message: "Re-run triggered for failed jobs", runID: runID}
+	}
+}
+
+func fetchArtifactsCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		artifacts, err := c.ListArtifacts(runID)
+		if err != nil {
+			return errMsg{err}
+		}
+		return artifactsLoadedMsg{runID: runID, artifacts: artifacts}
+	}
+}
+
+// downloadArtifactCmd extracts an artifact into a directory named after it in
+// the current directory.
+func downloadArtifactCmd(c *GitHubClient, a Artifact) tea.Cmd {
+	return func() tea.Msg {
+		dir := artifactDir(a)
+		files, err := c.DownloadArtifact(a.ID, dir)
+		return artifactDownloadedMsg{name: a.Name, dir: dir, files: len(files), err: err}
+	}
+}
+
+// artifactDir is the directory below the current one that a is extracted
+// into: its name, or artifact-<id> when the name would be the current or the
+// parent directory.
+func artifactDir(a Artifact) string {
+	dir := filepath.Base(a.Name)
+	if dir == "." || dir == ".." || dir == string(filepath.Separator) {
+		return fmt.Sprintf("artifact-%d", a.ID)
+	}
+	return dir
+}
+
+// fetchPreviousJobLogCmd finds job in an earlier run of its workflow and
+// downloads that job's log.
+func fetchPreviousJobLogCmd(c *GitHubClient, run WorkflowRun, job Job) tea.Cmd {
+	return func() tea.Msg {
+		prevRun, prevJob, err := c.FindPreviousJob(run, job.Name)
+		if err != nil || prevJob == nil {
+			return previousJobLogMsg{jobID: job.ID, err: err}
+		}
+		logs, err := c.GetJobLogs(prevJob.ID)
+		if err != nil {
+			return previousJobLogMsg{jobID: job.ID, err: err}
+		}
+		return previousJobLogMsg{jobID: job.ID, run: prevRun, job: prevJob, log: processLogLines(logs)}
+	}
+}
+
+func fetchRunScheduleCmd(c *GitHubClient, run WorkflowRun) tea.Cmd {
+	return func() tea.Msg {
+		schedule, err := c.GetRunSchedule(run)
+		return runScheduleMsg{runID: run.ID, schedule: schedule, err: err}
+	}
+}
+
+func fetchPendingDeploymentsCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		deployments, err := c.ListPendingDeployments(runID)
+		return pendingDeploymentsMsg{runID: runID, deployments: deployments, err: err}
+	}
+}
+
+func fetchReviewDeploymentsCmd(c *GitHubClient, run WorkflowRun) tea.Cmd {
+	return func() tea.Msg {
+		deployments, err := c.ListPendingDeployments(run.ID)
+		return reviewDeploymentsMsg{run: run, deployments: deployments, err: err}
+	}
+}
+
+func reviewDeploymentsCmd(c *GitHubClient, runID int64, envIDs []int64, envs string, approve bool, comment string) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.ReviewPendingDeployments(runID, envIDs, approve, comment); err != nil {
+			return errMsg{err}
+		}
+		return deploymentReviewedMsg{approved: approve, envs: envs}
+	}
+}
+
+func cancelRunCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.CancelRun(runID); err != nil {
+			return errMsg{err}
+		}
+		return cancelRunMsg{message: "Cancel requested", runID: runID}
+	}
+}
+
+func rerunAllCmd(c *GitHubClient, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.RerunAll(runID); err != nil {
+			return errMsg{err}
+		}
+		return rerunMsg{message: "Re-run triggered for all jobs", runID: runID}
+	}
+}
+
+func rerunJobCmd(c *GitHubClient, job Job) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.RerunJob(job.ID); err != nil {
+			return errMsg{err}
+		}
+		return rerunMsg{message: "Re-run triggered for " + job.Name, runID: job.RunID, jobID: job.ID}
+	}
+}
+
+// runOnLatestCmd starts run's workflow on the latest commit of its branch.
+func runOnLatestCmd(c *GitHubClient, run WorkflowRun) tea.Cmd {
+	return func() tea.Msg {
+		message, err := c.RunOnLatest(run)
+		if err != nil {
+			return errMsg{err}
+		}
+		return latestRunMsg(message)
+	}
+}
+
+func fetchPipelineInfoCmd(c *GitHubClient, jobID int64, gen int) tea.Cmd {
+	return func() tea.Msg {
+		info, err := c.GetPipelineServiceInfo(jobID)
+		if err != nil {
+			dbg("fetchPipelineInfoCmd: %v", err)
+			return pipelineInfoMsg{jobID: jobID, gen: gen, info: nil}
+		}
+		return pipelineInfoMsg{jobID: jobID, gen: gen, info: info}
+	}
+}
+
+func fetchStepLogsCmd(jobID int64, gen int, info *pipelineServiceInfo, steps []Step, maxFetchedID int) tea.Cmd {
+	return func() tea.Msg {
+		content, newMax, err := FetchNewStepLogs(info, steps, maxFetchedID)
+		return stepLogsMsg{jobID: jobID, gen: gen, content: content, maxFetchedID: newMax, err: err}
+	}
+}
+
+func probeLiveStrategyCmd(c *GitHubClient, job Job, gen int) tea.Cmd {
+	return func() tea.Msg {
+		return liveStrategyMsg{jobID: job.ID, gen: gen, strategy: c.ProbeLiveStrategy(job)}
+	}
+}
+
+func fetchLiveLogsCmd(c *GitHubClient, job Job, gen, changeID int) tea.Cmd {
+	return func() tea.Msg {
+		content, next, ok, err := c.GetLiveJobLogs(job.HTMLURL, changeID)
+		return liveLogsMsg{jobID: job.ID, gen: gen, content: content, nextChangeID: next, endpointOK: ok, err: err}
+	}
+}
+
+func fetchBlobLogsCmd(c *GitHubClient, jobID int64, gen int, blobURL string, offset int64, gzipped bool) tea.Cmd {
+	return func() tea.Msg {
+		if blobURL == "" {
+			u, err := c.GetJobLogBlobURL(jobID)
+			if err == nil && u == "" {
+				err = errLogsNotReady
+			}
+			if err != nil {
+				return blobLogsMsg{jobID: jobID, gen: gen, err: err}
+			}
+			blobURL = u
+		}
+		blob, err := FetchLogRange(blobURL, offset, gzipped)
+		return blobLogsMsg{jobID: jobID, gen: gen, url: blobURL, blob: blob, err: err}
+	}
+}
+
+// errLogsNotReady reports that a just-started job has no log blob yet.
+var errLogsNotReady = errors.New("logs not available yet")
+
+// Backoff between polls while waiting for a just-started job's logs to appear.
+const (
+	logWaitBase = 2 * time.Second
+	logWaitMax  = 30 * time.Second
+)
+
+// maxLiveAttempts is how many consecutive failed live fetches are tolerated
+// before streaming gives up and the steps panel is shown on its own.
+const maxLiveAttempts = 5
+
+// liveLogCmd issues the next live log fetch for the selected running job using
+// the host's known strategy, probing for one first if none is known yet.
+// Returns nil while a fetch is outstanding or streaming has given up.
+func (m *model) liveLogCmd() tea.Cmd {
+	if m.liveInFlight || !isRunning(m.selectedJob.Status) || m.liveFailedAttempts >= maxLiveAttempts {
+		return nil
+	}
+	if time.Now().Before(m.logWaitUntil) {
+		return nil
+	}
+	job := m.selectedJob
+	m.liveInFlight = true
+	switch m.client.LiveStrategy() {
+	case liveStrategyWeb:
+		return fetchLiveLogsCmd(m.client, job, m.liveGen, m.liveChangeID)
+	case liveStrategyBlob:
+		return fetchBlobLogsCmd(m.client, job.ID, m.liveGen, m.logBlobURL, m.logBlobOffset, m.logBlobGzip)
+	case liveStrategyPipeline:
+		if m.pipelineInfo == nil {
+			return fetchPipelineInfoCmd(m.client, job.ID, m.liveGen)
+		}
+		return fetchStepLogsCmd(job.ID, m.liveGen, m.pipelineInfo, job.Steps, m.stepLogsFetched)
+	default:
+		return probeLiveStrategyCmd(m.client, job, m.liveGen)
+	}
+}
+
+// liveMsgCurrent reports whether a live log message belongs to the job on
+// screen and its current stream. It also clears the in-flight flag so the next
+// poll tick can fetch again; a message from an earlier stream leaves it alone,
+// as the fetch in flight is the current stream's.
+func (m *model) liveMsgCurrent(jobID int64, gen int) bool {
+	if jobID != m.selectedJob.ID || gen != m.liveGen {
+		return false
+	}
+	m.liveInFlight = false
+	return m.state == stateLogs && isRunning(m.selectedJob.Status)
+}
+
+// liveFetchFailed counts a failed live fetch.
+func (m *model) liveFetchFailed(err error) {
+	m.liveFailedAttempts++
+	dbg("live fetch failed (%d/%d): %v", m.liveFailedAttempts, maxLiveAttempts, err)
+}
+
+// abandonLiveWeb falls back from the web endpoint to the log blob strategies.
+// Content streamed so far is dropped because the blob is re-read from the start.
+func (m *model) abandonLiveWeb() {
+	dbg("abandoning web live log strategy after %d failed attempts", m.liveFailedAttempts)
+	m.client.DisableLiveWeb()
+	m.liveFailedAttempts = 0
+	m.liveChangeID = 0
+	m.logRaw = ""
+	m.lastLogLength = 0
+	m.logLoaded = false
+	m.applyLogFilter()
+}
+
+// waitForLogs backs off before the next live fetch of a job whose logs do not
+// exist yet. Unlike liveFetchFailed this never gives up: the logs appear once
+// the runner has uploaded its first chunk.
+func (m *model) waitForLogs() {
+	m.logWaits++
+	delay := logWaitMax
+	if m.logWaits < 5 {
+		delay = logWaitBase << (m.logWaits - 1) // 2s, 4s, 8s, 16s, then 30s
+	}
+	m.logWaitUntil = time.Now().Add(delay)
+	dbg("logs not available yet (attempt %d), next try in %s", m.logWaits, delay)
+}
+
+// appendLiveLog appends streamed content to the log buffer and re-renders it.
+func (m *model) appendLiveLog(content string) {
+	m.liveFailedAttempts = 0
+	m.logWaits = 0
+	m.logWaitUntil = time.Time{}
+	m.liveStreaming = true
+	if content == "" {
+		return
+	}
+	if m.logRaw != "" {
+		m.logRaw += "\n"
+	}
+	m.logRaw += content
+	m.logLoaded = true
+	m.applyLogFilter()
+}
+
+// blobLines returns the complete lines of a range read from the log blob,
+// starting with the partial line the previous range ended on. Ranges end at
+// byte offsets rather than line ends, so the range's own trailing partial line
+// is kept for the next one.
+func (m *model) blobLines(chunk string) string {
+	text := m.logBlobPartial + chunk
+	i := strings.LastIndexByte(text, '\n')
+	if i < 0 {
+		m.logBlobPartial = text
+		return ""
+	}
+	m.logBlobPartial = text[i+1:]
+	return processLogLines(text[:i])
+}
+
+// resetLiveState clears all running-log streaming state for the selected job
+// and starts a new stream generation, so responses to fetches still in flight
+// are dropped.
+func (m *model) resetLiveState() {
+	m.liveGen++
+	m.liveStreaming = false
+	m.liveInFlight = false
+	m.liveChangeID = 0
+	m.liveLogs = ""
+	m.liveFailedAttempts = 0
+	m.logBlobURL = ""
+	m.logBlobOffset = 0
+	m.logBlobGzip = false
+	m.logBlobPartial = ""
+	m.logWaits = 0
+	m.logWaitUntil = time.Time{}
+	m.pipelineInfo = nil
+	m.stepLogsFetched = 0
+}
+
+// restartLiveStream drops the streamed log and every piece of streaming state,
+// then streams the running job again from its first line. Unlike a refresh it
+// keeps the filter and view settings; it is the way out of a stream that went
+// stale or picked up duplicates.
+func (m *model) restartLiveStream() tea.Cmd {
+	m.resetLiveState()
+	m.logRaw = ""
+	m.lastLogLength = 0
+	m.logLoaded = false
+	m.applyLogFilter()
+	return m.liveLogCmd()
+}
+
+// showError ends the pending request and shows its error in the status line.
+func (m *model) showError(err error) {
+	m.loading = false
+	m.dispatchInFlight = false
+	m.statusMsg = fmt.Sprintf("error: %v", err)
+	if limited, ok := m.client.rateLimitMessage(err); ok {
+		m.statusMsg = limited
 	}
 }
 
 // ─── Init ─────────────────────────────────────────────────────────────────────
 
 func (m model) Init() tea.Cmd {
+	if m.state == stateRuns {
+		// Restoring a session or following a deep link: main has already
+		// switched to the runs list.
+		cmds := []tea.Cmd{m.spinner.Tick, fetchRunsCmd(m.client), m.runsPollCmd()}
+		if m.deepLink != nil {
+			cmds = append(cmds, fetchDeepLinkCmd(m.client, m.deepLink.RunID, m.deepLink.JobID))
+		}
+		return tea.Batch(cmds...)
+	}
+	if m.state == statePRs {
+		return tea.Batch(m.spinner.Tick, fetchPRsCmd(m.client))
+	}
 	return m.spinner.Tick
 }
 
@@ -247,7 +1431,41 @@ func (m model) Init() tea.Cmd {
 // numMenuItems is the number of items in the main menu.
 const numMenuItems = 2
 
+// statusExpiry is how long a confirmation stays in the breadcrumb. Errors and
+// progress messages stay until the next action replaces them.
+const statusExpiry = 4 * time.Second
+
+// showSuccess shows a confirmation in the breadcrumb, which clears itself after
+// statusExpiry.
+func (m *model) showSuccess(text string) {
+	m.statusMsg = icons.success + " " + text
+	m.statusExpires = true
+}
+
+func clearStatusCmd(seq int) tea.Cmd {
+	return tea.Tick(statusExpiry, func(time.Time) tea.Msg {
+		return clearStatusMsg{seq: seq}
+	})
+}
+
+// Update wraps update to schedule the expiry of confirmation messages, so the
+// breadcrumb goes back to showing the current path after a few seconds.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	prevStatus := m.statusMsg
+	m.statusExpires = false
+	next, cmd := m.update(msg)
+	nm, ok := next.(model)
+	if !ok || nm.statusMsg == prevStatus {
+		return next, cmd
+	}
+	nm.statusSeq++
+	if !nm.statusExpires {
+		return nm, cmd
+	}
+	return nm, tea.Batch(cmd, clearStatusCmd(nm.statusSeq))
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -260,16 +1478,108 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.jobsList.SetSize(msg.Width, listH)
 		m.prsList.SetSize(msg.Width, listH)
 		m.workflowsList.SetSize(msg.Width, listH)
-		m.runsList.SetDelegate(runDelegate{width: msg.Width})
-		m.jobsList.SetDelegate(jobDelegate{width: msg.Width})
-		m.prsList.SetDelegate(prDelegate{width: msg.Width})
+		m.artifactsList.SetSize(msg.Width, max(1, listH-1))
+		m.artifactsList.SetDelegate(artifactDelegate{width: msg.Width})
+		m.annotationsList.SetSize(msg.Width, max(1, listH-1))
+		m.annotationsList.SetDelegate(annotationDelegate{width: msg.Width})
+		m.historyList.SetSize(msg.Width, max(1, listH-1))
+		m.runsList.SetDelegate(runDelegate{width: msg.Width, timeFormat: m.config.TimeFormat})
+		m.prsList.SetDelegate(prDelegate{width: msg.Width, timeFormat: m.config.TimeFormat})
 		m.workflowsList.SetDelegate(workflowDelegate{width: msg.Width})
+		m.historyList.SetDelegate(historyDelegate{width: msg.Width, timeFormat: m.config.TimeFormat})
+		m.resizeJobsList()
 		m.updateSizes()
+		if m.showHelp {
+			m.openHelp()
+		}
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 
 	case tea.KeyMsg:
+		// Any key takes over from a session restore still replaying.
+		m.restore = nil
+
+		// A confirmation overlay captures all input until answered.
+		if m.confirm != nil {
+			switch msg.String() {
+			case "y", "enter":
+				action := m.confirm.onYes
+				m.confirm = nil
+				return m, action
+			case "n", "esc", "b":
+				m.confirm = nil
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		if m.review != nil {
+			return m.updateReview(msg)
+		}
+		if m.prComment != nil {
+			return m.updatePRComment(msg)
+		}
+
+		// The help overlay scrolls on its own until closed.
+		if m.showHelp {
+			switch msg.String() {
+			case "ctrl+c":
+				return m.quit()
+			case "?", "esc", "q":
+				m.showHelp = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.helpViewport, cmd = m.helpViewport.Update(msg)
+			return m, cmd
+		}
+
+		// Branch search bar on the menu: type a branch name, enter lists its runs.
+		if m.state == stateMenu && m.branchSearchMode {
+			switch msg.String() {
+			case "esc":
+				m.branchSearchMode = false
+				m.branchSearch = ""
+			case "enter":
+				branch := strings.TrimSpace(m.branchSearch)
+				m.branchSearchMode = false
+				m.branchSearch = ""
+				if branch == "" {
+					return m, nil
+				}
+				m.state = stateRuns
+				m.loading = true
+				m.statusMsg = ""
+				m.selectedPR = nil
+				m.branchFilter = branch
+				m.runsPolling = true
+				return m, tea.Batch(m.runsList.SetItems(nil), m.runsCmd(), m.runsPollCmd())
+			case "backspace":
+				if len(m.branchSearch) > 0 {
+					runes := []rune(m.branchSearch)
+					m.branchSearch = string(runes[:len(runes)-1])
+				}
+			case "ctrl+u":
+				m.branchSearch = ""
+			case "ctrl+c":
+				return m.quit()
+			default:
+				if len(msg.Runes) > 0 {
+					m.branchSearch += string(msg.Runes)
+				}
+			}
+			return m, nil
+		}
+
 		// Main menu navigation — handle before everything else.
 		if m.state == stateMenu {
 			switch msg.String() {
+			case "/":
+				m.branchSearchMode = true
+				m.branchSearch = ""
+				return m, nil
 			case "up", "k":
 				if m.menuIndex > 0 {
 					m.menuIndex--
@@ -287,8 +1597,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.loading = true
 					m.statusMsg = ""
 					m.selectedPR = nil
+					m.branchFilter = ""
 					m.runsPolling = true
-					return m, tea.Batch(fetchRunsCmd(m.client), runsPollCmd())
+					return m, tea.Batch(m.runsList.SetItems(nil), fetchRunsCmd(m.client), m.runsPollCmd())
 				case 1: // Pull Requests
 					m.state = statePRs
 					m.loading = true
@@ -306,6 +1617,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.runsList, cmd = m.runsList.Update(msg)
 			return m, cmd
 		}
+		if m.state == stateJobs && m.jobsList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.jobsList, cmd = m.jobsList.Update(msg)
+			return m, cmd
+		}
+		if m.state == stateWorkflows && m.workflowsList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.workflowsList, cmd = m.workflowsList.Update(msg)
+			return m, cmd
+		}
+		if m.state == statePRs && m.prsList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.prsList, cmd = m.prsList.Update(msg)
+			return m, cmd
+		}
 
 		// While the log filter bar is active, handle input for the filter.
 		if m.state == stateLogs && m.logFilterMode {
@@ -350,18 +1676,122 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Dispatch form keyboard handling — fully handled here, always returns early.
-		if m.state == stateDispatchForm {
-			key := msg.String()
-
-			// Keys that always apply regardless of focus.
-			switch key {
-			case "ctrl+c":
-				return m, tea.Quit
+		// While the log search bar is active, typing searches incrementally.
+		if m.state == stateLogs && m.logSearchMode {
+			switch msg.String() {
 			case "esc":
-				m.state = stateWorkflows
-				m.formFields = nil
-				m.formButton = 0
+				m.logSearch = ""
+				m.logSearchMode = false
+				m.applyLogSearch()
+				m.updateSizes()
+			case "enter":
+				m.logSearchMode = false
+				m.updateSizes()
+			case "backspace":
+				if len(m.logSearch) > 0 {
+					runes := []rune(m.logSearch)
+					m.logSearch = string(runes[:len(runes)-1])
+					m.applyLogSearch()
+				}
+			case "ctrl+u":
+				m.logSearch = ""
+				m.applyLogSearch()
+			case "up", "ctrl+p":
+				m.nextMatch(-1)
+			case "down", "ctrl+n":
+				m.nextMatch(1)
+			default:
+				if len(msg.Runes) > 0 {
+					m.logSearch += string(msg.Runes)
+					m.applyLogSearch()
+				}
+			}
+			return m, nil
+		}
+
+		// The job summary panel scrolls on its own until closed.
+		if m.state == stateLogs && m.showSummary {
+			switch msg.String() {
+			case "ctrl+c", "q":
+				return m.quit()
+			case "S", "esc", "b":
+				m.showSummary = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.summaryViewport, cmd = m.summaryViewport.Update(msg)
+			return m, cmd
+		}
+
+		// The log comparison scrolls on its own until closed.
+		if m.state == stateLogs && m.showDiff {
+			switch msg.String() {
+			case "ctrl+c", "q":
+				return m.quit()
+			case "V", "esc", "b":
+				m.showDiff = false
+				return m, nil
+			case "]":
+				m.nextDiff(1)
+				return m, nil
+			case "[":
+				m.nextDiff(-1)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.diffViewport, cmd = m.diffViewport.Update(msg)
+			return m, cmd
+		}
+
+		// Dispatch preview: confirm with enter/y, return to the form with esc/b/n.
+		if m.state == stateDispatchForm && m.dispatchPreview {
+			switch msg.String() {
+			case "ctrl+c":
+				return m.quit()
+			case "enter", "y":
+				m.dispatchPreview = false
+				ref := m.dispatchRef()
+				m.loading = true
+				m.statusMsg = "Dispatching workflow…"
+				m.dispatchInFlight = true
+				return m, triggerDispatchCmd(m.client, m.selectedWorkflow, ref, m.dispatchInputs())
+			case "ctrl+y":
+				m.copyDispatchCommand()
+			case "esc", "b", "n":
+				m.dispatchPreview = false
+			}
+			return m, nil
+		}
+
+		// Dispatch form keyboard handling — fully handled here, always returns early.
+		if m.state == stateDispatchForm {
+			key := msg.String()
+
+			// Keys that always apply regardless of focus.
+			switch key {
+			case "ctrl+c":
+				return m.quit()
+			case "ctrl+y":
+				m.copyDispatchCommand()
+				return m, nil
+			case "ctrl+e":
+				m.toggleExpandedField()
+				return m, nil
+			case "ctrl+l":
+				if m.formButton == 0 && m.formActiveField == 0 {
+					m.loading = true
+					m.statusMsg = "Refreshing branches and tags…"
+					return m, fetchRefOptionsCmd(m.client, true)
+				}
+				return m, nil
+			case "ctrl+r":
+				m.resetDispatchForm()
+				m.showSuccess("Reset to the workflow's defaults")
+				return m, nil
+			case "esc":
+				m.state = stateWorkflows
+				m.formFields = nil
+				m.formButton = 0
 				return m, nil
 			case "tab":
 				if m.formButton != 0 {
@@ -413,65 +1843,42 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.formButton = 0
 					return m, nil
 				}
-				// Build button — dispatch
+				// Expanded fields take enter as a line break.
+				if m.formButton == 0 && len(m.formFields) > 0 && m.formFields[m.formActiveField].area != nil {
+					return m, m.updateArea(msg)
+				}
+				// Build button — show the dispatch preview before sending.
 				if m.formButton == 2 {
-					ref := ""
-					if len(m.formFields) > 0 {
-						ref = m.formFields[0].input.Value()
-						if m.formFields[0].fieldType == "ref" {
-							filter := strings.ToLower(ref)
-							switch m.refSection {
-							case 1:
-								if fb := filterRefs(m.refBranches, filter); len(fb) > 0 {
-									idx := m.refBranchIdx
-									if idx >= len(fb) {
-										idx = len(fb) - 1
-									}
-									ref = fb[idx]
-								}
-							case 2:
-								if ft := filterRefs(m.refTags, filter); len(ft) > 0 {
-									idx := m.refTagIdx
-									if idx >= len(ft) {
-										idx = len(ft) - 1
-									}
-									ref = ft[idx]
-								}
-							}
+					for i, f := range m.formFields {
+						if f.fieldType == "number" && !validNumber(f.input.Value()) {
+							m.statusMsg = fmt.Sprintf("%s must be a number, not %q", f.label, f.input.Value())
+							// Back to the offending field.
+							m.formButton = 0
+							m.formActiveField = i
+							return m, m.formFields[i].input.Focus()
 						}
 					}
-					if ref == "" {
-						ref = m.defaultBranch
-						if ref == "" {
-							ref = "main"
-						}
-					}
-					inputs := make(map[string]string)
-					for _, f := range m.formFields[1:] {
-						if val := f.input.Value(); val != "" {
-							inputs[f.label] = val
-						}
-					}
-					m.loading = true
-					m.statusMsg = "Dispatching workflow…"
-					return m, triggerDispatchCmd(m.client, m.selectedWorkflow.ID, ref, inputs)
+					m.statusMsg = ""
+					m.dispatchPreview = true
+					return m, nil
 				}
 				// On ref field in list section: select the highlighted item into the input.
 				if len(m.formFields) > 0 && m.formActiveField == 0 && m.formFields[0].fieldType == "ref" {
 					filter := strings.ToLower(m.formFields[0].input.Value())
 					switch m.refSection {
 					case 1:
-						if fb := filterRefs(m.refBranches, filter); len(fb) > 0 {
-							idx := m.refBranchIdx
-							if idx >= len(fb) {
-								idx = len(fb) - 1
+						if row, ok := m.selectedBranchRow(filter); ok && row.header {
+							if m.refCollapsed == nil {
+								m.refCollapsed = make(map[string]bool)
 							}
-							m.formFields[0].input.SetValue(fb[idx])
+							m.refCollapsed[row.name] = !m.refCollapsed[row.name]
+						} else if ok {
+							m.formFields[0].input.SetValue(row.name)
 							m.formFields[0].input.Placeholder = ""
 							m.refSection = 0
 						}
 					case 2:
-						if ft := filterRefs(m.refTags, filter); len(ft) > 0 {
+						if ft := filterRefs(m.refTags, filter, m.config.RefMatch); len(ft) > 0 {
 							idx := m.refTagIdx
 							if idx >= len(ft) {
 								idx = len(ft) - 1
@@ -513,7 +1920,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					filter := strings.ToLower(f.input.Value())
 					if m.refSection == 1 {
-						fb := filterRefs(m.refBranches, filter)
+						fb := m.branchRows(filter)
 						if key == "up" || key == "k" {
 							if m.refBranchIdx > 0 {
 								m.refBranchIdx--
@@ -528,7 +1935,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 					}
 					if m.refSection == 2 {
-						ft := filterRefs(m.refTags, filter)
+						ft := filterRefs(m.refTags, filter, m.config.RefMatch)
 						if key == "up" || key == "k" {
 							if m.refTagIdx > 0 {
 								m.refTagIdx--
@@ -546,24 +1953,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					var cmd tea.Cmd
 					m.formFields[m.formActiveField].input, cmd = m.formFields[m.formActiveField].input.Update(msg)
 					newFilter := strings.ToLower(m.formFields[0].input.Value())
-					if fb := filterRefs(m.refBranches, newFilter); m.refBranchIdx >= len(fb) {
+					if fb := m.branchRows(newFilter); m.refBranchIdx >= len(fb) {
 						m.refBranchIdx = max(0, len(fb)-1)
 					}
-					if ft := filterRefs(m.refTags, newFilter); m.refTagIdx >= len(ft) {
+					if ft := filterRefs(m.refTags, newFilter, m.config.RefMatch); m.refTagIdx >= len(ft) {
 						m.refTagIdx = max(0, len(ft)-1)
 					}
 					return m, cmd
 				}
 
-				if f.fieldType == "choice" && (key == "up" || key == "k") && len(f.options) > 0 {
+				picker := f.fieldType == "choice" || f.fieldType == "environment"
+				if picker && (key == "up" || key == "k") && len(f.options) > 0 {
 					f.optionIdx = (f.optionIdx - 1 + len(f.options)) % len(f.options)
 					f.input.SetValue(f.options[f.optionIdx])
-					return m, nil
+					return m, m.envVarsCmd(f.input.Value())
 				}
-				if f.fieldType == "choice" && (key == "down" || key == "j") && len(f.options) > 0 {
+				if picker && (key == "down" || key == "j") && len(f.options) > 0 {
 					f.optionIdx = (f.optionIdx + 1) % len(f.options)
 					f.input.SetValue(f.options[f.optionIdx])
-					return m, nil
+					return m, m.envVarsCmd(f.input.Value())
 				}
 				if f.fieldType == "boolean" && (key == "up" || key == "k" || key == "down" || key == "j" || key == " ") {
 					if f.input.Value() == "true" {
@@ -573,9 +1981,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					return m, nil
 				}
+				if f.fieldType == "number" && len(msg.Runes) > 0 && !numberRunes(msg.Runes) {
+					return m, nil
+				}
+				if f.area != nil {
+					return m, m.updateArea(msg)
+				}
 				// Delegate all remaining input to the active textinput.
 				var cmd tea.Cmd
 				m.formFields[m.formActiveField].input, cmd = m.formFields[m.formActiveField].input.Update(msg)
+				if f.fieldType == "environment" {
+					cmd = tea.Batch(cmd, m.envVarsCmd(f.input.Value()))
+				}
 				return m, cmd
 			}
 			return m, nil
@@ -584,7 +2001,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.quit()
 
 		case "q":
 			if m.state == stateRuns && m.runsList.FilterState() == list.FilterApplied {
@@ -592,52 +2009,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.runsList, cmd = m.runsList.Update(msg)
 				return m, cmd
 			}
-			return m, tea.Quit
+			if m.state == stateJobs && m.jobsList.FilterState() == list.FilterApplied {
+				var cmd tea.Cmd
+				m.jobsList, cmd = m.jobsList.Update(msg)
+				return m, cmd
+			}
+			if m.state == stateWorkflows && m.workflowsList.FilterState() == list.FilterApplied {
+				var cmd tea.Cmd
+				m.workflowsList, cmd = m.workflowsList.Update(msg)
+				return m, cmd
+			}
+			return m.quit()
 
 		case "/":
+			if m.state == stateLogs && !isRunning(m.selectedJob.Status) {
+				m.logSearchMode = true
+				m.searchOrigin = m.logViewport.YOffset
+				m.updateSizes()
+				return m, nil
+			}
+
+		case "&":
 			if m.state == stateLogs && !isRunning(m.selectedJob.Status) {
 				m.logFilterMode = true
 				m.updateSizes()
 				return m, nil
 			}
 
+		case "n", "N":
+			if m.state == stateLogs && m.logSearch != "" {
+				if len(m.searchMatches) == 0 {
+					m.statusMsg = "Pattern not found: " + m.logSearch
+					return m, nil
+				}
+				if msg.String() == "n" {
+					m.nextMatch(1)
+				} else {
+					m.nextMatch(-1)
+				}
+				return m, nil
+			}
+
 		case "enter":
 			switch m.state {
 			case stateRuns:
 				if item, ok := m.runsList.SelectedItem().(runItem); ok {
-					m.selectedRun = item.run
-					m.state = stateJobs
-					m.loading = true
-					m.statusMsg = ""
-					m.jobsPolling = true
-					cmds = append(cmds, fetchJobsCmd(m.client, item.run.ID))
-					cmds = append(cmds, jobsPollCmd())
-					return m, tea.Batch(cmds...)
+					return m, m.openRun(item.run)
 				}
 			case stateJobs:
 				if item, ok := m.jobsList.SelectedItem().(jobItem); ok {
-					m.selectedJob = item.job
-					m.state = stateLogs
-					m.jobsPolling = false
-					m.logContent = ""
-					m.logRaw = ""
-					m.lastLogLength = 0
-					m.logLoaded = false
-					m.autoScroll = true
-					m.statusMsg = ""
-					m.logFilter = ""
-					m.logFilterMode = false
-					m.pipelineInfo = nil
-					m.stepLogsFetched = 0
-					m.updateSizes()
-					if isRunning(item.job.Status) {
-						cmds = append(cmds, fetchJobsCmd(m.client, m.selectedRun.ID))
-						cmds = append(cmds, logPollCmd())
-					} else {
-						cmds = append(cmds, fetchLogsCmd(m.client, item.job.ID))
-					}
-					return m, tea.Batch(cmds...)
+					return m, m.openLogs(item.job)
 				}
+			case stateLogs:
+				m.toggleGroup()
+				return m, nil
 			case statePRs:
 				if item, ok := m.prsList.SelectedItem().(prItem); ok {
 					pr := item.pr
@@ -648,7 +2073,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.runsPolling = true
 					return m, tea.Batch(
 						fetchRunsForPRCmd(m.client, pr.Head.SHA),
-						runsPollCmd(),
+						fetchChecksRollupCmd(m.client, pr.Head.SHA),
+						m.runsPollCmd(),
 					)
 				}
 			case stateWorkflows:
@@ -658,21 +2084,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.statusMsg = ""
 					return m, fetchWorkflowInputsCmd(m.client, item.wf)
 				}
+			case stateArtifacts:
+				if item, ok := m.artifactsList.SelectedItem().(artifactItem); ok {
+					if item.a.Expired {
+						m.statusMsg = fmt.Sprintf("%s has expired and can no longer be downloaded", item.a.Name)
+						return m, nil
+					}
+					m.loading = true
+					m.statusMsg = fmt.Sprintf("Downloading %s…", item.a.Name)
+					return m, downloadArtifactCmd(m.client, item.a)
+				}
+				return m, nil
+			case stateAnnotations:
+				if item, ok := m.annotationsList.SelectedItem().(annotationItem); ok {
+					if !item.a.hasFile() {
+						m.statusMsg = "This annotation is not attached to a file"
+						return m, nil
+					}
+					m.openSourceLocation(sourceLocation{Path: item.a.Path, Line: item.a.StartLine})
+				}
+				return m, nil
+			case stateDispatchHistory:
+				if item, ok := m.historyList.SelectedItem().(historyItem); ok {
+					rec := item.rec
+					m.confirm = &confirmPrompt{
+						message: fmt.Sprintf("Dispatch %s on %s again with the same inputs?", rec.Workflow, rec.Ref),
+						onYes:   func() tea.Msg { return redispatchMsg(rec) },
+					}
+				}
+				return m, nil
 			}
 
 		case "esc", "b":
 			switch m.state {
 			case stateJobs:
+				if m.jobsList.FilterState() == list.FilterApplied {
+					var cmd tea.Cmd
+					m.jobsList, cmd = m.jobsList.Update(msg)
+					return m, cmd
+				}
 				m.state = stateRuns
 				m.jobsPolling = false
 				m.jobsPollStartIDs = nil
+				m.prJobsRuns = nil
 				m.statusMsg = ""
 				return m, nil
 			case stateLogs:
 				m.state = stateJobs
 				m.statusMsg = ""
 				m.jobsPolling = true
-				cmds = append(cmds, jobsPollCmd())
+				cmds = append(cmds, m.jobsPollCmd())
+				if m.prJobsRuns != nil {
+					m.selectedRun = prJobsRun(m.selectedPR)
+					cmds = append(cmds, m.jobsCmd())
+				}
 				return m, tea.Batch(cmds...)
 			case stateRuns:
 				// If list filter is active, let the list clear it.
@@ -683,6 +2148,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.runsPolling = false
 				m.statusMsg = ""
+				m.branchFilter = ""
 				if m.selectedPR != nil {
 					m.selectedPR = nil
 					m.state = statePRs
@@ -691,19 +2157,46 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case statePRs:
+				if m.prsList.FilterState() == list.FilterApplied {
+					var cmd tea.Cmd
+					m.prsList, cmd = m.prsList.Update(msg)
+					return m, cmd
+				}
 				m.state = stateMenu
 				m.statusMsg = ""
 				return m, nil
 			case stateWorkflows:
+				if m.workflowsList.FilterState() == list.FilterApplied {
+					var cmd tea.Cmd
+					m.workflowsList, cmd = m.workflowsList.Update(msg)
+					return m, cmd
+				}
 				m.state = stateRuns
 				m.statusMsg = ""
 				return m, nil
+			case stateDispatchHistory:
+				m.state = stateWorkflows
+				m.statusMsg = ""
+				return m, nil
+			case stateArtifacts:
+				m.state = stateJobs
+				m.statusMsg = ""
+				m.jobsPolling = true
+				return m, tea.Batch(m.jobsCmd(), m.jobsPollCmd())
+			case stateAnnotations:
+				m.state = stateLogs
+				m.statusMsg = ""
+				if isRunning(m.selectedJob.Status) {
+					return m, m.logPollCmd()
+				}
+				return m, nil
 			}
 
 		case "d":
 			if m.state == stateRuns {
 				m.state = stateWorkflows
 				m.statusMsg = ""
+				m.workflowsList.ResetFilter()
 				cmds = append(cmds, fetchWorkflowsCmd(m.client))
 				if m.defaultBranch == "" {
 					cmds = append(cmds, fetchDefaultBranchCmd(m.client))
@@ -715,30 +2208,42 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch m.state {
 			case stateRuns:
 				if item, ok := m.runsList.SelectedItem().(runItem); ok {
+					if reason := m.rerunBlocker(item.run, true); reason != "" {
+						m.statusMsg = reason
+						return m, nil
+					}
 					m.statusMsg = "Triggering rerun of failed jobs…"
 					m.loading = true
 					cmds = append(cmds, rerunFailedCmd(m.client, item.run.ID))
 					return m, tea.Batch(cmds...)
 				}
 			case stateJobs:
+				if reason := m.rerunBlocker(m.jobsViewRun(), true); reason != "" {
+					m.statusMsg = reason
+					return m, nil
+				}
 				m.statusMsg = "Triggering rerun of failed jobs…"
 				m.loading = true
-				cmds = append(cmds, rerunFailedCmd(m.client, m.selectedRun.ID))
+				cmds = append(cmds, rerunFailedCmd(m.client, m.jobsViewRunID()))
 				return m, tea.Batch(cmds...)
 			case stateLogs:
 				m.logLoaded = false
 				m.lastLogLength = 0
 				m.logRaw = ""
+				m.logTimestamped = ""
 				m.logContent = ""
 				m.logFilter = ""
 				m.logFilterMode = false
-				m.pipelineInfo = nil
-				m.stepLogsFetched = 0
+				m.logFiltered = false
+				m.logSearch = ""
+				m.logSearchMode = false
+				m.resetLiveState()
 				if isRunning(m.selectedJob.Status) {
 					cmds = append(cmds, fetchJobsCmd(m.client, m.selectedRun.ID))
-					cmds = append(cmds, logPollCmd())
+					cmds = append(cmds, m.logPollCmd())
+					cmds = append(cmds, m.liveLogCmd())
 				} else {
-					cmds = append(cmds, fetchLogsCmd(m.client, m.selectedJob.ID))
+					cmds = append(cmds, fetchLogsCmd(m.client, m.selectedJob))
 				}
 				return m, tea.Batch(cmds...)
 			case statePRs:
@@ -747,20 +2252,127 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, fetchPRsCmd(m.client)
 			}
 
+		case "A":
+			if m.state == stateJobs {
+				runID := m.jobsViewRunID()
+				if runID == 0 {
+					return m, nil
+				}
+				m.state = stateArtifacts
+				m.jobsPolling = false
+				m.statusMsg = ""
+				if runID != m.artifactsRunID {
+					m.artifactsRunID = runID
+					m.loading = true
+					cmds = append(cmds, m.artifactsList.SetItems(nil))
+				}
+				cmds = append(cmds, fetchArtifactsCmd(m.client, runID))
+				return m, tea.Batch(cmds...)
+			}
+
+		case "x":
+			if m.state != stateRuns && m.state != stateJobs {
+				break
+			}
+			run := m.jobsViewRun()
+			if m.state == stateRuns {
+				item, ok := m.runsList.SelectedItem().(runItem)
+				if !ok {
+					return m, nil
+				}
+				run = item.run
+			}
+			if !m.runCancellable(run) {
+				m.statusMsg = "Run is not cancellable"
+				return m, nil
+			}
+			m.statusMsg = "Requesting cancellation…"
+			m.loading = true
+			return m, cancelRunCmd(m.client, run.ID)
+
 		case "R":
 			switch m.state {
 			case stateRuns:
 				if item, ok := m.runsList.SelectedItem().(runItem); ok {
+					if reason := m.rerunBlocker(item.run, false); reason != "" {
+						m.statusMsg = reason
+						return m, nil
+					}
 					m.statusMsg = "Triggering rerun of all jobs…"
 					m.loading = true
 					cmds = append(cmds, rerunAllCmd(m.client, item.run.ID))
 					return m, tea.Batch(cmds...)
 				}
 			case stateJobs:
+				if reason := m.rerunBlocker(m.jobsViewRun(), false); reason != "" {
+					m.statusMsg = reason
+					return m, nil
+				}
 				m.statusMsg = "Triggering rerun of all jobs…"
 				m.loading = true
-				cmds = append(cmds, rerunAllCmd(m.client, m.selectedRun.ID))
+				cmds = append(cmds, rerunAllCmd(m.client, m.jobsViewRunID()))
 				return m, tea.Batch(cmds...)
+			case stateLogs:
+				if !isRunning(m.selectedJob.Status) {
+					m.statusMsg = "Job is not running"
+					return m, nil
+				}
+				m.statusMsg = "stream restarted"
+				return m, m.restartLiveStream()
+			}
+
+		case "l":
+			if m.state != stateRuns && m.state != stateJobs {
+				break
+			}
+			run := m.jobsViewRun()
+			if m.state == stateRuns {
+				item, ok := m.runsList.SelectedItem().(runItem)
+				if !ok {
+					return m, nil
+				}
+				run = item.run
+			}
+			if run.HeadBranch == "" {
+				m.statusMsg = "Run has no branch to run on"
+				return m, nil
+			}
+			sha := run.HeadSHA[:min(7, len(run.HeadSHA))]
+			message := fmt.Sprintf("Run %s again on the latest commit of %s, not on %s?", run.Name, run.HeadBranch, sha)
+			if run.Event == "workflow_dispatch" {
+				// The API doesn't return a run's inputs, so they can't be
+				// carried over.
+				message = fmt.Sprintf("Dispatch %s again on the latest commit of %s with its default inputs, not on %s?", run.Name, run.HeadBranch, sha)
+			}
+			m.confirm = &confirmPrompt{
+				message: message,
+				onYes:   runOnLatestCmd(m.client, run),
+			}
+			return m, nil
+
+		case "v":
+			if m.state == statePRs {
+				if item, ok := m.prsList.SelectedItem().(prItem); ok {
+					m.confirm = &confirmPrompt{
+						message: fmt.Sprintf("Approve #%d %s?", item.pr.Number, truncate(item.pr.Title, 50)),
+						onYes:   approvePRCmd(m.client, item.pr),
+					}
+				}
+				return m, nil
+			}
+
+		case "m":
+			if m.state == statePRs {
+				if item, ok := m.prsList.SelectedItem().(prItem); ok {
+					m.prComment = newPRCommentPrompt(item.pr)
+				}
+				return m, nil
+			}
+
+		case "e":
+			if m.state == stateLogs {
+				m.nextError()
+				return m, nil
 			}
 
 		case "tab", "ctrl+r":
@@ -768,137 +2380,526 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case stateRuns:
 				m.loading = true
 				m.statusMsg = ""
+				cmds = append(cmds, m.runsCmd())
 				if m.selectedPR != nil {
-					cmds = append(cmds, fetchRunsForPRCmd(m.client, m.selectedPR.Head.SHA))
-				} else {
-					cmds = append(cmds, fetchRunsCmd(m.client))
+					cmds = append(cmds, fetchChecksRollupCmd(m.client, m.selectedPR.Head.SHA))
 				}
 				return m, tea.Batch(cmds...)
 			case statePRs:
 				m.loading = true
 				m.statusMsg = ""
 				return m, fetchPRsCmd(m.client)
+			case stateLogs:
+				if msg.String() == "tab" {
+					m.moveGroupCursor(1)
+					return m, nil
+				}
 			}
 
-		case "a":
+		case "shift+tab":
 			if m.state == stateLogs {
-				m.autoScroll = !m.autoScroll
-				if m.autoScroll {
-					m.logViewport.GotoBottom()
-				}
+				m.moveGroupCursor(-1)
+				return m, nil
 			}
 
-		case "g":
+		case " ":
 			if m.state == stateLogs {
-				m.logViewport.GotoTop()
-				m.autoScroll = false
+				m.toggleGroup()
 				return m, nil
 			}
 
-		case "G":
+		case "Z":
 			if m.state == stateLogs {
-				m.logViewport.GotoBottom()
+				m.toggleAllGroups()
 				return m, nil
 			}
 
-		case "o":
+		case "f":
 			switch m.state {
 			case stateRuns:
-				if item, ok := m.runsList.SelectedItem().(runItem); ok {
-					if item.run.HTMLURL != "" {
-						if err := OpenInBrowser(item.run.HTMLURL); err != nil {
-							m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
-						} else {
-							m.statusMsg = "✓ Opened run in browser"
-						}
-					}
+				cmds = append(cmds, m.cycleRunFilter())
+				if m.runStatusFilter != runFilterAll && len(m.runsList.Items()) == 0 {
+					m.statusMsg = fmt.Sprintf("No %s runs among the loaded runs", m.runStatusFilter)
+				} else {
+					m.statusMsg = ""
 				}
+				return m, tea.Batch(cmds...)
+			case stateJobs:
+				m.selectNextJob("failed", func(j Job) bool { return j.Conclusion == "failure" })
 				return m, nil
 			case statePRs:
 				if item, ok := m.prsList.SelectedItem().(prItem); ok {
-					if item.pr.HTMLURL != "" {
-						if err := OpenInBrowser(item.pr.HTMLURL); err != nil {
-							m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
-						} else {
-							m.statusMsg = "✓ Opened PR in browser"
-						}
-					}
-				}
-				return m, nil
-			case stateJobs:
-				if item, ok := m.jobsList.SelectedItem().(jobItem); ok {
-					if item.job.HTMLURL != "" {
-						if err := OpenInBrowser(item.job.HTMLURL); err != nil {
-							m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
-						} else {
-							m.statusMsg = "✓ Opened job in browser"
-						}
-					} else {
-						m.statusMsg = "Job URL not available"
-					}
+					m.loading = true
+					m.statusMsg = "Finding failing job…"
+					return m, findFailingJobCmd(m.client, item.pr)
 				}
-				return m, nil
 			case stateLogs:
-				if m.selectedJob.HTMLURL != "" {
-					if err := OpenInBrowser(m.selectedJob.HTMLURL); err != nil {
-						m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+				if isRunning(m.selectedJob.Status) {
+					m.followStep = !m.followStep
+					if m.followStep {
+						m.logPinned = false
+						m.autoScroll = false
+						m.scrollToActiveStep()
 					} else {
-						m.statusMsg = "✓ Opened job in browser"
+						m.autoScroll = true
+						m.logViewport.GotoBottom()
 					}
-				} else {
-					m.statusMsg = "Job URL not available"
+					return m, nil
 				}
-				return m, nil
 			}
 
-		case "up":
+		case "#":
 			if m.state == stateLogs {
-				if m.logViewport.YOffset > 0 {
-					m.logViewport.YOffset--
-					m.autoScroll = false
-				}
+				m.stepNumbers = !m.stepNumbers
 				return m, nil
 			}
 
-		case "pgup":
+		case "D":
 			if m.state == stateLogs {
-				m.logViewport.YOffset = max(0, m.logViewport.YOffset-m.logViewport.Height/2)
-				m.autoScroll = false
+				m.collapseDupes = !m.collapseDupes
+				m.applyLogFilter()
 				return m, nil
 			}
 
-		case "down":
-			if m.state == stateLogs {
-				totalHeight := lipgloss.Height(m.logContent)
-				maxOffset := max(0, totalHeight-m.logViewport.Height)
-				if m.logViewport.YOffset < maxOffset {
-					m.logViewport.YOffset++
+		case "J":
+			if m.state == stateJobs || m.state == stateLogs {
+				job := m.selectedJob
+				if m.state == stateJobs {
+					item, ok := m.jobsList.SelectedItem().(jobItem)
+					if !ok {
+						return m, nil
+					}
+					job = item.job
 				}
-				if m.logViewport.YOffset >= maxOffset {
-					m.autoScroll = true
-					m.logViewport.GotoBottom()
+				if reason := m.jobRerunBlocker(job); reason != "" {
+					m.statusMsg = reason
+					return m, nil
+				}
+				m.statusMsg = "Triggering rerun of " + job.Name + "…"
+				m.loading = true
+				return m, rerunJobCmd(m.client, job)
+			}
+			if m.state == stateRuns && m.selectedPR != nil {
+				var runs []WorkflowRun
+				for _, item := range m.runsList.Items() {
+					if ri, ok := item.(runItem); ok {
+						runs = append(runs, ri.run)
+					}
 				}
+				if len(runs) == 0 {
+					m.statusMsg = "No runs for this PR yet"
+					return m, nil
+				}
+				m.prJobsRuns = runs
+				m.selectedRun = prJobsRun(m.selectedPR)
+				m.state = stateJobs
+				m.loading = true
+				m.statusMsg = ""
+				m.jobsPolling = true
+				cmds = append(cmds, m.jobsList.SetItems(nil), m.jobsCmd(), m.jobsPollCmd())
+				return m, tea.Batch(cmds...)
+			}
+
+		case "?":
+			if m.state != stateDispatchForm {
+				m.openHelp()
 				return m, nil
 			}
 
-		case "pgdn":
+		case "u":
+			if m.state == stateJobs {
+				if item, ok := m.jobsList.SelectedItem().(jobItem); ok && !item.refreshing {
+					item.refreshing = true
+					return m, tea.Batch(
+						m.jobsList.SetItem(m.jobsList.GlobalIndex(), item),
+						fetchJobCmd(m.client, item.job.ID),
+					)
+				}
+				return m, nil
+			}
+
+		case "p":
+			if m.state == stateJobs {
+				m.selectNextJob("in progress", func(j Job) bool { return isRunning(j.Status) })
+				return m, nil
+			}
+			if m.state == stateLogs && isRunning(m.selectedJob.Status) {
+				m.togglePin()
+				return m, nil
+			}
+
+		case "E":
 			if m.state == stateLogs {
-				totalHeight := lipgloss.Height(m.logContent)
-				maxOffset := max(0, totalHeight-m.logViewport.Height)
-				m.logViewport.YOffset = min(maxOffset, m.logViewport.YOffset+m.logViewport.Height/2)
-				if m.logViewport.YOffset >= maxOffset {
-					m.autoScroll = true
+				return m, m.openAnnotations()
+			}
+			if m.state == stateJobs {
+				run := m.jobsViewRun()
+				if run.ID == 0 {
+					// The aggregated PR view's placeholder run, with no job
+					// selected to pick a real run by.
+					m.statusMsg = "Select a job to export its run"
+					return m, nil
+				}
+				repo := m.client.owner + "/" + m.client.repo
+				path, err := exportRunSummaryHTML(repo, run, m.jobsViewJobs())
+				if err != nil {
+					m.statusMsg = fmt.Sprintf("error exporting run: %v", err)
+				} else if err := OpenInBrowser(path); err != nil {
+					m.showSuccess("Exported run summary to " + path)
+				} else {
+					m.showSuccess("Exported run summary to " + path + " and opened it")
+				}
+				return m, nil
+			}
+
+		case "s":
+			if m.state == stateJobs || m.state == stateLogs {
+				if m.width < sidebarMinWidth {
+					m.statusMsg = fmt.Sprintf("Sidebar needs a terminal at least %d columns wide", sidebarMinWidth)
+					return m, nil
+				}
+				m.sidebarHidden = !m.sidebarHidden
+				m.resizeJobsList()
+				m.updateSizes()
+				return m, nil
+			}
+
+		case "i":
+			if m.state == stateJobs {
+				if m.prJobsRuns != nil {
+					m.statusMsg = "Run metrics are shown for a single run; open one from the runs list"
+					return m, nil
+				}
+				m.showRunMetrics = !m.showRunMetrics
+				m.resizeJobsList()
+				return m, m.runTimingCmd()
+			}
+
+		case "a":
+			if m.state == stateRuns {
+				item, ok := m.runsList.SelectedItem().(runItem)
+				if !ok {
+					return m, nil
+				}
+				if item.run.Status != "waiting" {
+					m.statusMsg = "Run is not waiting for a deployment review"
+					return m, nil
+				}
+				m.loading = true
+				m.statusMsg = "Loading pending deployments…"
+				return m, fetchReviewDeploymentsCmd(m.client, item.run)
+			}
+			if m.state == stateJobs {
+				var prompt *reviewPrompt
+				if m.pendingRunID == m.selectedRun.ID {
+					prompt = newReviewPrompt(m.selectedRun, m.pendingDeploys)
+				}
+				if prompt == nil {
+					m.statusMsg = "No pending deployment you can review"
+					return m, nil
+				}
+				m.review = prompt
+				return m, nil
+			}
+			if m.state == stateLogs {
+				m.followStep = false
+				m.logPinned = false
+				m.autoScroll = !m.autoScroll
+				if m.autoScroll {
 					m.logViewport.GotoBottom()
 				}
+			}
+
+		case "g":
+			if m.state == stateLogs {
+				m.followStep = false
+				m.logPinned = false
+				m.logViewport.GotoTop()
+				m.autoScroll = false
 				return m, nil
 			}
 
+		case "G":
+			if m.state == stateLogs {
+				m.followStep = false
+				m.logPinned = false
+				m.logViewport.GotoBottom()
+				return m, nil
+			}
+
+		case "o":
+			switch m.state {
+			case stateRuns:
+				if item, ok := m.runsList.SelectedItem().(runItem); ok {
+					if item.run.HTMLURL != "" {
+						if err := OpenInBrowser(item.run.HTMLURL); err != nil {
+							m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+						} else {
+							m.showSuccess("Opened run in browser")
+						}
+					}
+				}
+				return m, nil
+			case statePRs:
+				if item, ok := m.prsList.SelectedItem().(prItem); ok {
+					if item.pr.HTMLURL != "" {
+						if err := OpenInBrowser(item.pr.HTMLURL); err != nil {
+							m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+						} else {
+							m.showSuccess("Opened PR in browser")
+						}
+					}
+				}
+				return m, nil
+			case stateJobs:
+				if item, ok := m.jobsList.SelectedItem().(jobItem); ok {
+					if item.job.HTMLURL != "" {
+						if err := OpenInBrowser(item.job.HTMLURL); err != nil {
+							m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+						} else {
+							m.showSuccess("Opened job in browser")
+						}
+					} else {
+						m.statusMsg = "Job URL not available"
+					}
+				}
+				return m, nil
+			case stateLogs:
+				if m.selectedJob.HTMLURL != "" {
+					if err := OpenInBrowser(m.selectedJob.HTMLURL); err != nil {
+						m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+					} else {
+						m.showSuccess("Opened job in browser")
+					}
+				} else {
+					m.statusMsg = "Job URL not available"
+				}
+				return m, nil
+			}
+
+		case "O":
+			if m.state == stateLogs {
+				step, ok := failedStep(m.selectedJob)
+				switch {
+				case !ok:
+					m.statusMsg = "No failed step in this job"
+				case m.selectedJob.HTMLURL == "":
+					m.statusMsg = "Job URL not available"
+				default:
+					if err := OpenInBrowser(stepURL(m.selectedJob, step)); err != nil {
+						m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+					} else {
+						m.showSuccess(fmt.Sprintf("Opened failed step %q in browser", step.Name))
+					}
+				}
+				return m, nil
+			}
+
+		case "H":
+			if m.state == stateWorkflows {
+				records := loadDispatchHistory(m.client.host + "/" + m.client.owner + "/" + m.client.repo)
+				items := make([]list.Item, len(records))
+				for i, rec := range records {
+					items[i] = historyItem{rec}
+				}
+				m.state = stateDispatchHistory
+				m.statusMsg = ""
+				return m, m.historyList.SetItems(items)
+			}
+			if m.state == stateLogs {
+				m.compactHeader = !m.compactHeader
+				m.updateSizes()
+				return m, nil
+			}
+
+		case "ctrl+w":
+			if m.state == stateLogs && m.logLoaded {
+				top := m.logLineAt(m.logViewport.YOffset)
+				m.wrapEnabled = !m.wrapEnabled
+				m.renderLogContent()
+				if m.autoScroll {
+					m.logViewport.GotoBottom()
+				} else {
+					m.logViewport.SetYOffset(m.logRow(top))
+				}
+				return m, nil
+			}
+
+		case "t":
+			if m.state == stateLogs && m.logLoaded {
+				top := m.logLineAt(m.logViewport.YOffset)
+				m.showTimestamps = !m.showTimestamps
+				if m.showTimestamps && m.logTimestamped == "" {
+					m.statusMsg = "Timestamps are shown once the job has completed"
+				}
+				m.renderLogContent()
+				if m.autoScroll {
+					m.logViewport.GotoBottom()
+				} else {
+					m.logViewport.SetYOffset(m.logRow(top))
+				}
+				return m, nil
+			}
+
+		case "M":
+			if m.state == statePRs {
+				return m, m.togglePRsMine()
+			}
+			if m.state == stateLogs && m.logLoaded {
+				m.plainLogs = !m.plainLogs
+				yOff := m.logViewport.YOffset
+				m.applyLogFilter()
+				if !m.autoScroll && !m.logPinned && !m.followStep {
+					m.logViewport.SetYOffset(yOff)
+				}
+				return m, nil
+			}
+
+		case "Y":
+			var run WorkflowRun
+			switch m.state {
+			case stateRuns:
+				if item, ok := m.runsList.SelectedItem().(runItem); ok {
+					run = item.run
+				}
+			case stateJobs:
+				run = m.jobsViewRun()
+			case stateLogs:
+				run = m.selectedRun
+			}
+			if run.ID == 0 {
+				return m, nil
+			}
+			if u := m.client.WorkflowFileURL(run); u == "" {
+				m.statusMsg = "Workflow file not available for this run"
+			} else if err := OpenInBrowser(u); err != nil {
+				m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+			} else {
+				m.showSuccess("Opened workflow file as of the run's commit")
+			}
+			return m, nil
+
+		case "W":
+			if err := OpenInBrowser(m.client.ActionsURL()); err != nil {
+				m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+			} else {
+				m.showSuccess("Opened Actions tab in browser")
+			}
+			return m, nil
+
+		case "up":
+			if m.state == stateLogs {
+				m.scrollLogs(-1)
+				return m, nil
+			}
+
+		case "pgup":
+			if m.state == stateLogs {
+				m.scrollLogs(-m.logViewport.Height / 2)
+				m.autoScroll = false
+				return m, nil
+			}
+
+		case "down":
+			if m.state == stateLogs {
+				m.scrollLogs(1)
+				return m, nil
+			}
+
+		case "pgdn":
+			if m.state == stateLogs {
+				m.scrollLogs(m.logViewport.Height / 2)
+				return m, nil
+			}
+
+		case "F":
+			if m.state == stateLogs {
+				// Prefer an ::error command in the log; otherwise ask the annotations API.
+				if loc := parseErrorCommand(m.logRaw); loc != nil {
+					m.openSourceLocation(*loc)
+					return m, nil
+				}
+				m.statusMsg = "Looking up error annotations…"
+				return m, fetchErrorAnnotationCmd(m.client, m.selectedJob.ID)
+			}
+
 		case "c":
+			if m.state == stateJobs {
+				text, n := runLinksText(m.jobsViewRun(), m.jobsViewJobs())
+				if err := clipboard.WriteAll(text); err != nil {
+					m.statusMsg = fmt.Sprintf("error copying links: %v", err)
+				} else {
+					m.showSuccess(fmt.Sprintf("Copied run link and %d job links to clipboard", n))
+				}
+				return m, nil
+			}
+			if m.state == stateLogs {
+				if err := clipboard.WriteAll(m.config.Redact.applyCopy(m.logRaw)); err != nil {
+					m.statusMsg = fmt.Sprintf("error copying logs: %v", err)
+				} else {
+					m.showSuccess("Logs copied to clipboard (timestamps stripped)")
+				}
+				return m, nil
+			}
+
+		case "L":
+			if link := m.deepLinkCommand(); link != "" {
+				if err := clipboard.WriteAll(link); err != nil {
+					m.statusMsg = fmt.Sprintf("error copying link: %v", err)
+				} else {
+					m.showSuccess("Copied: " + link)
+				}
+				return m, nil
+			}
+
+		case "S":
+			if m.state == stateLogs {
+				m.showSummary = true
+				if m.summaryJobID != m.selectedJob.ID {
+					m.summaryJobID = m.selectedJob.ID
+					m.summaryLoaded = false
+					return m, fetchJobSummaryCmd(m.client, m.selectedJob.ID)
+				}
+				return m, nil
+			}
+
+		case "V":
+			if m.state == stateLogs && m.logLoaded && !isRunning(m.selectedJob.Status) {
+				m.showDiff = true
+				if m.diffJobID != m.selectedJob.ID {
+					m.diffJobID = m.selectedJob.ID
+					m.diffLoaded = false
+					return m, fetchPreviousJobLogCmd(m.client, m.selectedRun, m.selectedJob)
+				}
+				return m, nil
+			}
+
+		case "P":
+			if m.state == stateRuns && m.selectedPR != nil {
+				if err := OpenInBrowser(m.selectedPR.HTMLURL); err != nil {
+					m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+				} else {
+					m.showSuccess("Opened PR conversation in browser")
+				}
+				return m, nil
+			}
+
+		case "C":
+			if m.state == stateRuns && m.selectedPR != nil {
+				if err := OpenInBrowser(m.selectedPR.HTMLURL + "/checks"); err != nil {
+					m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+				} else {
+					m.showSuccess("Opened PR checks in browser")
+				}
+				return m, nil
+			}
 			if m.state == stateLogs {
-				if err := clipboard.WriteAll(m.logRaw); err != nil {
+				if m.logTimestamped == "" {
+					m.statusMsg = "Timestamped log not available for this job yet"
+				} else if err := clipboard.WriteAll(m.config.Redact.applyCopy(m.logTimestamped)); err != nil {
 					m.statusMsg = fmt.Sprintf("error copying logs: %v", err)
 				} else {
-					m.statusMsg = "✓ Logs copied to clipboard"
+					m.showSuccess("Logs copied to clipboard (with timestamps)")
 				}
 				return m, nil
 			}
@@ -906,21 +2907,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// ─── Data messages ─────────────────────────────────────────────────────
 
+	case deepLinkMsg:
+		m.deepLink = nil
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("error opening link: %v", msg.err)
+			break
+		}
+		// Only jump if the user hasn't navigated away from the runs list yet.
+		if m.state == stateRuns {
+			if msg.jobID != 0 {
+				m.restore = &session{JobID: msg.jobID}
+			}
+			cmds = append(cmds, m.openRun(msg.run))
+		}
+
 	case runsLoadedMsg:
+		prSHA := ""
+		if m.selectedPR != nil {
+			prSHA = m.selectedPR.Head.SHA
+		}
+		if msg.prSHA != prSHA || msg.branch != m.branchFilter {
+			break // a response for a list the user has since left
+		}
 		m.loading = false
-		items := make([]list.Item, len(msg))
-		for i, r := range msg {
-			items[i] = runItem{r}
+		m.pollFailures = 0
+		if len(msg.runs) == 0 && m.branchFilter != "" && m.state == stateRuns {
+			m.statusMsg = fmt.Sprintf("No recent runs on a branch matching %q", m.branchFilter)
+		}
+		// Keep the cursor on the same run when a rerun or a new run shifts the
+		// ordering.
+		var selectedID int64
+		if item, ok := m.runsList.SelectedItem().(runItem); ok {
+			selectedID = item.run.ID
+		}
+		cmds = append(cmds, m.setRuns(msg.runs, selectedID))
+		cmds = append(cmds, m.runProgressCmds()...)
+		if m.restore != nil && m.state == stateRuns {
+			cmds = append(cmds, m.restoreRun())
 		}
-		cmds = append(cmds, m.runsList.SetItems(items))
 
 	case prsLoadedMsg:
 		m.loading = false
-		items := make([]list.Item, len(msg))
-		for i, pr := range msg {
-			items[i] = prItem{pr}
+		cmds = append(cmds, m.setPRs(msg))
+		// Refreshed on every load: checks and mergeability change without the
+		// head moving.
+		cmds = append(cmds, fetchPRStatusesCmd(m.client))
+		if m.restore != nil && m.state == statePRs {
+			m.restorePR()
 		}
-		cmds = append(cmds, m.prsList.SetItems(items))
 
 	case workflowsLoadedMsg:
 		m.loading = false
@@ -931,13 +2965,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.workflowsList.SetItems(items))
 
 	case workflowInputsMsg:
-		ref := m.defaultBranch
-		if ref == "" {
-			ref = "main"
-		}
-		m.formFields = buildDispatchFormFields([]WorkflowInput(msg), ref)
+		m.formFields = buildDispatchFormFields([]WorkflowInput(msg), m.dispatchDefaultRef())
 		m.formActiveField = 0
 		m.formButton = 0
+		m.dispatchPreview = false
 		m.refBranches = nil
 		m.refTags = nil
 		m.refSection = 0
@@ -945,44 +2976,120 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.refTagIdx = 0
 		m.state = stateDispatchForm
 		m.loading = false
+		m.statusMsg = ""
+		if rec, ok := lastDispatch(m.client.host+"/"+m.client.owner+"/"+m.client.repo, m.selectedWorkflow.Path); ok {
+			prefillDispatchForm(m.formFields, rec)
+			m.statusMsg = fmt.Sprintf("Prefilled from your dispatch of %s · ctrl+r resets to the defaults", rec.At.Local().Format("Jan 2 15:04"))
+		}
 		if len(m.formFields) > 0 {
 			blinkCmd := m.formFields[0].input.Focus()
 			cmds = append(cmds, blinkCmd)
 		}
-		cmds = append(cmds, fetchRefOptionsCmd(m.client))
+		cmds = append(cmds, fetchRefOptionsCmd(m.client, false))
+		if slices.ContainsFunc(m.formFields, func(f formField) bool { return f.fieldType == "environment" }) {
+			cmds = append(cmds, fetchEnvironmentsCmd(m.client))
+		}
+
+	case environmentsMsg:
+		for i := range m.formFields {
+			f := &m.formFields[i]
+			if f.fieldType != "environment" {
+				continue
+			}
+			f.options = msg
+			if idx := slices.Index(f.options, f.input.Value()); idx >= 0 {
+				f.optionIdx = idx
+			}
+			cmds = append(cmds, m.envVarsCmd(f.input.Value()))
+		}
+
+	case envVarsMsg:
+		if msg.err != nil {
+			// Usually a token that may dispatch but not read variables.
+			dbg("variables of environment %s: %v", msg.env, msg.err)
+			break
+		}
+		m.envVars[msg.env] = msg.vars
 
 	case refOptionsMsg:
 		m.refBranches = msg.branches
 		m.refTags = msg.tags
-		// Pre-select: find the default branch in the list and highlight it.
+		if msg.refresh {
+			m.loading = false
+			m.showSuccess(fmt.Sprintf("%d branches and %d tags", len(m.refBranches), len(m.refTags)))
+			filter := ""
+			if len(m.formFields) > 0 {
+				filter = strings.ToLower(m.formFields[0].input.Value())
+			}
+			m.refBranchIdx = min(m.refBranchIdx, max(0, len(m.branchRows(filter))-1))
+			m.refTagIdx = min(m.refTagIdx, max(0, len(filterRefs(m.refTags, filter, m.config.RefMatch))-1))
+			break
+		}
+		// A configured default ref that the repo doesn't have falls back to the
+		// default branch.
+		configured := m.config.dispatchRef(m.client.host, m.client.owner, m.client.repo)
+		if configured != "" && !slices.Contains(m.refBranches, configured) && !slices.Contains(m.refTags, configured) {
+			m.dispatchRefMissing = true
+			if len(m.formFields) > 0 && m.formFields[0].input.Value() == configured {
+				m.formFields[0].input.SetValue(m.dispatchDefaultRef())
+			}
+			m.statusMsg = fmt.Sprintf("Configured dispatch ref %q not found, using %s", configured, m.dispatchDefaultRef())
+		}
+		// Pre-select: find the default ref in the list and highlight it.
 		// Clear the textinput so it acts as an empty filter (showing all refs).
-		// Keep the branch name as a placeholder so the user still sees the default.
+		// Keep the ref name as a placeholder so the user still sees the default.
 		if len(m.formFields) > 0 {
 			val := m.formFields[0].input.Value()
-			for i, b := range m.refBranches {
-				if b == val {
-					m.refSection = 1
-					m.refBranchIdx = i
-					m.formFields[0].input.Placeholder = val
-					m.formFields[0].input.SetValue("")
-					break
-				}
+			if i := slices.IndexFunc(m.branchRows(""), func(r refRow) bool { return !r.header && r.name == val }); i >= 0 {
+				m.refSection = 1
+				m.refBranchIdx = i
+				m.formFields[0].input.Placeholder = val
+				m.formFields[0].input.SetValue("")
+			} else if i := slices.Index(m.refTags, val); i >= 0 {
+				m.refSection = 2
+				m.refTagIdx = i
+				m.formFields[0].input.Placeholder = val
+				m.formFields[0].input.SetValue("")
 			}
 		}
 
+	case redispatchMsg:
+		rec := dispatchRecord(msg)
+		m.loading = true
+		m.statusMsg = "Dispatching workflow…"
+		m.dispatchInFlight = true
+		cmds = append(cmds, triggerDispatchCmd(m.client, rec.workflow(), rec.Ref, rec.Inputs))
+
+	case prReviewedMsg:
+		if msg.err != nil {
+			m.showError(msg.err)
+			break
+		}
+		m.loading = false
+		m.showSuccess(msg.message)
+
 	case dispatchTriggeredMsg:
 		m.loading = false
-		m.statusMsg = string(msg)
+		m.dispatchInFlight = false
+		m.showSuccess(string(msg))
 		m.state = stateRuns
 		m.formFields = nil
 		// Refresh runs after a short moment (dispatch takes time to appear)
-		cmds = append(cmds, fetchRunsCmd(m.client))
+		cmds = append(cmds, m.runsCmd())
+
+	case latestRunMsg:
+		m.loading = false
+		m.showSuccess(string(msg))
+		if m.state == stateRuns {
+			cmds = append(cmds, m.runsCmd())
+		}
 
 	case defaultBranchMsg:
 		m.defaultBranch = string(msg)
 
 	case jobsLoadedMsg:
 		m.loading = false
+		m.pollFailures = 0
 
 		if m.jobsPollStartIDs != nil {
 			hasNew := false
@@ -1001,11 +3108,28 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		runID := m.selectedRun.ID
 		oldJobs := m.lastJobsForRun[runID]
 
+		// While a job from the aggregated PR view is open, the log poll fetches
+		// only its run; keep the combined list intact for the way back.
+		aggregatedInLogs := m.prJobsRuns != nil && m.state == stateLogs
+
+		workflows := make(map[int64]string, len(m.prJobsRuns))
+		for _, r := range m.prJobsRuns {
+			workflows[r.ID] = r.Name
+		}
+		// Keep the indicator on rows whose single-job refresh is still out.
+		refreshing := make(map[int64]bool)
+		for _, it := range m.jobsList.Items() {
+			if ji, ok := it.(jobItem); ok && ji.refreshing {
+				refreshing[ji.job.ID] = true
+			}
+		}
 		items := make([]list.Item, len(msg))
 		for i, j := range msg {
-			items[i] = jobItem{j}
+			items[i] = jobItem{job: j, workflow: workflows[j.RunID], refreshing: refreshing[j.ID]}
+		}
+		if !aggregatedInLogs {
+			cmds = append(cmds, m.jobsList.SetItems(items))
 		}
-		cmds = append(cmds, m.jobsList.SetItems(items))
 
 		var newJobs []Job
 		for _, j := range msg {
@@ -1021,37 +3145,186 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		if m.state == stateJobs && len(newJobs) > 0 {
-			for i, item := range items {
+			for i, item := range m.jobsList.VisibleItems() {
 				if ji, ok := item.(jobItem); ok && ji.job.ID == newJobs[0].ID {
 					m.jobsList.Select(i)
-					m.statusMsg = "✓ Jumped to re-triggered job"
+					m.showSuccess("Jumped to re-triggered job")
 					break
 				}
 			}
 		}
 
+		// A run whose jobs all finished may still create jobs that need them,
+		// so ask for the run until GitHub reports it completed; runRefreshedMsg
+		// then says how it went.
+		oldByRun := jobsByRun(oldJobs)
+		for id, jobs := range jobsByRun(msg) {
+			if anyRunning(jobs) {
+				continue
+			}
+			if _, waiting := m.completingRuns[id]; waiting || anyRunning(oldByRun[id]) {
+				if m.completingRuns == nil {
+					m.completingRuns = make(map[int64][]Job)
+				}
+				m.completingRuns[id] = jobs
+				cmds = append(cmds, fetchRunCmd(m.client, id))
+			}
+		}
 		m.lastJobsForRun[runID] = msg
+		if len(msg) == 0 && m.prJobsRuns == nil && runID != 0 {
+			// The run in the list may be stale; only a finished run is known
+			// to have no jobs coming.
+			if m.runHasNoJobs() {
+				m.jobsPolling = false
+			} else {
+				cmds = append(cmds, fetchRunCmd(m.client, runID))
+			}
+		}
+		cmds = append(cmds, m.prefetchFailedLogs(msg)...)
+		if runID != 0 && m.awaitingReview(msg) {
+			cmds = append(cmds, fetchPendingDeploymentsCmd(m.client, runID))
+		} else if m.pendingRunID == runID && len(m.pendingDeploys) > 0 {
+			m.pendingDeploys = nil
+			m.resizeJobsList()
+		}
+		if m.restore != nil && m.state == stateJobs {
+			cmds = append(cmds, m.restoreJob(msg))
+		}
 
 		if m.state == stateLogs {
 			for _, j := range msg {
 				if j.ID == m.selectedJob.ID {
 					wasRunning := isRunning(m.selectedJob.Status)
 					m.selectedJob = j
+					if m.followStep {
+						m.scrollToActiveStep()
+					}
 					isNowDone := wasRunning && !isRunning(m.selectedJob.Status)
 					if isNowDone {
-						m.pipelineInfo = nil
-						cmds = append(cmds, fetchLogsCmd(m.client, m.selectedJob.ID))
+						m.resetLiveState()
+						cmds = append(cmds, fetchLogsCmd(m.client, m.selectedJob))
 					}
 					break
 				}
 			}
 		}
 
+	case runRefreshedMsg:
+		if jobs, ok := m.completingRuns[msg.ID]; ok && msg.Status == "completed" {
+			// The watched run just finished: say how it went and ring the bell.
+			// A success clears itself like other confirmations; anything else
+			// stays until the next action.
+			delete(m.completingRuns, msg.ID)
+			m.statusMsg = watchSummary(WorkflowRun(msg), jobs)
+			m.statusExpires = msg.Conclusion == "success"
+			cmds = append(cmds, bellCmd)
+		}
+		if msg.ID == m.selectedRun.ID {
+			m.selectedRun = WorkflowRun(msg)
+			if m.runHasNoJobs() {
+				m.jobsPolling = false
+			}
+		}
+
+	case jobRefreshedMsg:
+		for i, item := range m.jobsList.Items() {
+			ji, ok := item.(jobItem)
+			if !ok || ji.job.ID != msg.jobID {
+				continue
+			}
+			ji.refreshing = false
+			if msg.err != nil {
+				m.statusMsg = fmt.Sprintf("error refreshing job: %v", msg.err)
+			} else {
+				ji.job = msg.job
+			}
+			cmds = append(cmds, m.jobsList.SetItem(i, ji))
+			break
+		}
+		if msg.err == nil {
+			jobs := m.lastJobsForRun[m.selectedRun.ID]
+			for i := range jobs {
+				if jobs[i].ID == msg.jobID {
+					jobs[i] = msg.job
+				}
+			}
+		}
+
+	case previousJobLogMsg:
+		if msg.jobID != m.diffJobID {
+			break
+		}
+		if msg.err != nil || msg.job == nil {
+			// Allow retrying with V.
+			m.diffJobID = 0
+			m.showDiff = false
+			if msg.err != nil {
+				m.statusMsg = fmt.Sprintf("error fetching previous run: %v", msg.err)
+			} else {
+				m.statusMsg = "No earlier run of this job to compare with"
+			}
+			break
+		}
+		m.diffLoaded = true
+		m.diffPrevRun, m.diffPrevJob = *msg.run, *msg.job
+		m.diffRows = diffLines(strings.Split(msg.log, "\n"), strings.Split(m.logRaw, "\n"))
+		m.setDiffContent()
+		m.diffViewport.GotoTop()
+		m.diffHunkIdx = -1
+		m.nextDiff(1)
+
+	case jobSummaryMsg:
+		if msg.jobID != m.summaryJobID {
+			break
+		}
+		m.summaryLoaded = true
+		m.summaryText = msg.text
+		if msg.err != nil {
+			// Allow retrying with S.
+			m.summaryJobID = 0
+			m.showSummary = false
+			m.statusMsg = fmt.Sprintf("error fetching job summary: %v", msg.err)
+			break
+		}
+		m.setSummaryContent()
+		m.summaryViewport.GotoTop()
+
+	case errorAnnotationMsg:
+		if msg.jobID != m.selectedJob.ID || m.state != stateLogs {
+			break
+		}
+		switch {
+		case msg.err != nil:
+			m.statusMsg = fmt.Sprintf("error fetching annotations: %v", msg.err)
+		case msg.loc == nil:
+			m.statusMsg = "No file/line associated with this job's errors"
+		default:
+			m.openSourceLocation(*msg.loc)
+		}
+
 	case logsLoadedMsg:
-		rawContent := string(msg)
+		m.pollFailures = 0
+		rawContent := msg.content
 		dbg("logsLoadedMsg: %d bytes, jobStatus=%s", len(rawContent), m.selectedJob.Status)
+		if msg.completed && msg.timestamped != "" {
+			m.logCache.put(msg.jobID, msg.timestamped)
+		}
+		// The annotations view belongs to the open log, which esc returns to.
+		if msg.jobID != m.selectedJob.ID || (m.state != stateLogs && m.state != stateAnnotations) {
+			break // the log of a job the user has since left
+		}
 		if rawContent != "" {
+			if msg.jobID == m.diffJobID && rawContent != m.logRaw {
+				// The comparison was made against the log as it was before.
+				m.diffJobID = 0
+				m.diffLoaded = false
+				if m.showDiff && msg.jobID == m.selectedJob.ID {
+					m.diffJobID = msg.jobID
+					cmds = append(cmds, fetchPreviousJobLogCmd(m.client, m.selectedRun, m.selectedJob))
+				}
+			}
 			m.logRaw = rawContent
+			m.logTimestamped = msg.timestamped
 			m.lastLogLength = len(rawContent)
 			m.logLoaded = true
 			m.applyLogFilter()
@@ -1063,17 +3336,176 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case logPollTickMsg:
-		if m.state == stateLogs {
+		if msg.gen == m.pollGen && m.state == stateLogs {
+			if m.client.RateLimited() {
+				cmds = append(cmds, m.logPollCmd())
+				break
+			}
 			if isRunning(m.selectedJob.Status) {
 				cmds = append(cmds, fetchJobsCmd(m.client, m.selectedRun.ID))
-				cmds = append(cmds, logPollCmd())
+				cmds = append(cmds, m.logPollCmd())
+				cmds = append(cmds, m.liveLogCmd())
 			} else {
-				cmds = append(cmds, fetchLogsCmd(m.client, m.selectedJob.ID))
+				cmds = append(cmds, fetchLogsCmd(m.client, m.selectedJob))
+			}
+		}
+
+	case failingJobMsg:
+		m.loading = false
+		if m.state != statePRs {
+			break // user navigated away while resolving
+		}
+		if msg.job == nil {
+			m.statusMsg = noFailingJobStatus(msg.runs)
+			if m.statusMsg == "" {
+				m.showSuccess("All checks passing")
+			}
+			break
+		}
+		// Populate the runs and jobs lists so esc walks back through the usual path.
+		pr := msg.pr
+		m.selectedPR = &pr
+		m.selectedRun = msg.run
+		m.prJobsRuns = nil
+		cmds = append(cmds, m.setRuns(msg.runs, msg.run.ID))
+		jobItems := make([]list.Item, len(msg.jobs))
+		for i, j := range msg.jobs {
+			jobItems[i] = jobItem{job: j}
+		}
+		cmds = append(cmds, m.jobsList.SetItems(jobItems))
+		for i, j := range msg.jobs {
+			if j.ID == msg.job.ID {
+				m.jobsList.Select(i)
+			}
+		}
+		m.lastJobsForRun[msg.run.ID] = msg.jobs
+		if !m.runsPolling {
+			m.runsPolling = true
+			cmds = append(cmds, m.runsPollCmd())
+		}
+		cmds = append(cmds, m.openLogs(*msg.job))
+		return m, tea.Batch(cmds...)
+
+	case logPrefetchedMsg:
+		delete(m.logPrefetching, msg.jobID)
+		if msg.err != nil {
+			dbg("logPrefetchedMsg: job %d: %v", msg.jobID, msg.err)
+		} else if msg.content != "" {
+			m.logCache.put(msg.jobID, msg.content)
+			// Continue with any failed jobs that didn't fit in the first batch.
+			cmds = append(cmds, m.prefetchFailedLogs(m.lastJobsForRun[m.selectedRun.ID])...)
+		}
+
+	case artifactsLoadedMsg:
+		if msg.runID != m.artifactsRunID {
+			break
+		}
+		m.loading = false
+		items := make([]list.Item, len(msg.artifacts))
+		for i, a := range msg.artifacts {
+			items[i] = artifactItem{a}
+		}
+		cmds = append(cmds, m.artifactsList.SetItems(items))
+
+	case annotationsLoadedMsg:
+		if msg.jobID != m.annotationsJobID {
+			break
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("error loading annotations: %v", msg.err)
+			m.annotationsJobID = 0 // nothing cached; the next E fetches again
+			break
+		}
+		cmds = append(cmds, m.annotationsList.SetItems(annotationItems(msg.annotations, m.config.Redact)))
+
+	case artifactDownloadedMsg:
+		m.loading = false
+		switch {
+		case errors.Is(msg.err, errArtifactExpired):
+			m.statusMsg = fmt.Sprintf("%s has expired and can no longer be downloaded", msg.name)
+		case msg.err != nil:
+			m.statusMsg = fmt.Sprintf("error downloading %s: %v", msg.name, msg.err)
+		default:
+			abs, _ := filepath.Abs(msg.dir)
+			m.showSuccess(fmt.Sprintf("Extracted %d files to %s", msg.files, abs))
+		}
+
+	case runScheduleMsg:
+		if msg.err != nil {
+			dbg("schedule of run %d: %v", msg.runID, msg.err)
+			break
+		}
+		if msg.runID == m.runScheduleRunID {
+			m.runSchedule = msg.schedule
+			m.resizeJobsList()
+		}
+
+	case pendingDeploymentsMsg:
+		if msg.runID != m.selectedRun.ID {
+			break
+		}
+		if msg.err != nil {
+			dbg("pending deployments for run %d: %v", msg.runID, msg.err)
+			break
+		}
+		m.pendingRunID = msg.runID
+		m.pendingDeploys = msg.deployments
+		m.resizeJobsList()
+
+	case reviewDeploymentsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("error loading pending deployments: %v", msg.err)
+			break
+		}
+		m.review = newReviewPrompt(msg.run, msg.deployments)
+		if m.review == nil {
+			m.statusMsg = "No pending deployment you can review"
+		} else {
+			m.statusMsg = ""
+		}
+
+	case deploymentReviewedMsg:
+		m.loading = false
+		if msg.approved {
+			m.showSuccess("Approved deployment to " + msg.envs + "; the run continues")
+		} else {
+			m.showSuccess("Rejected deployment to " + msg.envs + "; the run will fail")
+		}
+		// The run leaves the waiting state; refresh so it shows.
+		switch m.state {
+		case stateRuns:
+			cmds = append(cmds, m.runsCmd())
+		case stateJobs:
+			cmds = append(cmds, m.jobsCmd())
+		}
+
+	case cancelRunMsg:
+		m.loading = false
+		m.showSuccess(msg.message)
+		// Keep polling so the run and its jobs flip to cancelled.
+		switch m.state {
+		case stateRuns:
+			cmds = append(cmds, m.runsCmd())
+		case stateJobs:
+			cmds = append(cmds, m.jobsCmd())
+			if !m.jobsPolling {
+				m.jobsPolling = true
+				cmds = append(cmds, m.jobsPollCmd())
 			}
 		}
 
 	case rerunMsg:
-		m.statusMsg = msg.message
+		m.showSuccess(msg.message)
+		m.invalidateRunLogs(msg.runID)
+		// A job re-run from its log: back to the jobs to jump to the new attempt.
+		if msg.jobID != 0 && m.state == stateLogs && m.selectedJob.ID == msg.jobID {
+			m.state = stateJobs
+			if m.prJobsRuns != nil {
+				m.selectedRun = prJobsRun(m.selectedPR)
+			}
+		}
 		m.jobsPollStartIDs = make(map[int64]bool)
 		for _, item := range m.jobsList.Items() {
 			if ji, ok := item.(jobItem); ok {
@@ -1085,44 +3517,187 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if !m.jobsPolling {
 			m.jobsPolling = true
-			cmds = append(cmds, jobsPollCmd())
+			cmds = append(cmds, m.jobsPollCmd())
 		}
 
 	case jobsPollTickMsg:
-		if m.jobsPolling {
+		if msg.gen == m.pollGen && m.jobsPolling {
+			if m.client.RateLimited() {
+				cmds = append(cmds, m.jobsPollCmd())
+				break
+			}
 			if m.state == stateJobs {
-				cmds = append(cmds, fetchJobsCmd(m.client, m.selectedRun.ID))
+				cmds = append(cmds, m.jobsCmd())
 			}
-			cmds = append(cmds, jobsPollCmd())
+			cmds = append(cmds, m.jobsPollCmd())
 		}
 
 	case runsPollTickMsg:
-		if m.runsPolling {
-			if m.selectedPR != nil {
-				cmds = append(cmds, fetchRunsForPRCmd(m.client, m.selectedPR.Head.SHA))
-			} else {
-				cmds = append(cmds, fetchRunsCmd(m.client))
+		if msg.gen == m.pollGen && m.runsPolling {
+			// Out of API quota: keep ticking without requests until the reset.
+			if m.client.RateLimited() {
+				cmds = append(cmds, m.runsPollCmd())
+				break
+			}
+			cmds = append(cmds, m.runsCmd())
+			if m.selectedPR != nil && m.state == stateRuns {
+				cmds = append(cmds, fetchChecksRollupCmd(m.client, m.selectedPR.Head.SHA))
+			}
+			cmds = append(cmds, m.runsPollCmd())
+		}
+
+	case runProgressMsg:
+		delete(m.progressIn, msg.runID)
+		if msg.err != nil {
+			dbg("run %d progress: %v", msg.runID, msg.err)
+			break
+		}
+		m.runProgress[msg.runID] = msg.progress
+		for i, item := range m.runsList.Items() {
+			if ri, ok := item.(runItem); ok && ri.run.ID == msg.runID {
+				ri.progress = msg.progress
+				cmds = append(cmds, m.runsList.SetItem(i, ri))
+				break
+			}
+		}
+
+	case prStatusesMsg:
+		for _, st := range msg {
+			m.prChecks[st.HeadSHA] = st.Checks
+			if st.Mergeable != nil {
+				// Unknown while GitHub computes it; the next refresh picks it up.
+				m.prMergeable[st.Number] = st
+			}
+		}
+		for i, item := range m.prsList.Items() {
+			if pi, ok := item.(prItem); ok {
+				if rollup, ok := m.prChecks[pi.pr.Head.SHA]; ok {
+					pi.checks = &rollup
+				}
+				pi.pr = m.withMergeability(pi.pr)
+				cmds = append(cmds, m.prsList.SetItem(i, pi))
+			}
+		}
+
+	case loginMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("error looking up your login: %v", msg.err)
+			break
+		}
+		m.login = msg.login
+		if m.state == statePRs {
+			m.statusMsg = ""
+			m.prsMine = true
+			cmds = append(cmds, m.setPRs(m.loadedPRs))
+		}
+
+	case checksRollupMsg:
+		m.prChecks[msg.sha] = msg.rollup
+		for i, item := range m.prsList.Items() {
+			if pi, ok := item.(prItem); ok && pi.pr.Head.SHA == msg.sha {
+				pi.checks = &msg.rollup
+				cmds = append(cmds, m.prsList.SetItem(i, pi))
 			}
-			cmds = append(cmds, runsPollCmd())
+		}
+
+	case clearStatusMsg:
+		if msg.seq == m.statusSeq {
+			m.statusMsg = ""
 		}
 
 	case errMsg:
-		m.loading = false
-		m.statusMsg = fmt.Sprintf("error: %v", msg.err)
+		m.showError(msg.err)
+
+	case pollErrMsg:
+		m.showError(msg.err)
+		if retry, ok := m.backOffPolling(); ok {
+			m.statusMsg += fmt.Sprintf(" · retrying in %s", retry)
+			cmds = append(cmds, m.restartPolling()...)
+		}
+
+	case runTimingMsg:
+		if msg.runID == m.runTimingRunID {
+			m.runTiming = msg.timing
+		}
+
+	case liveStrategyMsg:
+		if !m.liveMsgCurrent(msg.jobID, msg.gen) {
+			break
+		}
+		if msg.strategy == liveStrategyUnknown {
+			// Usually a just-started job without logs; probe again after a backoff.
+			m.waitForLogs()
+			break
+		}
+		cmds = append(cmds, m.liveLogCmd())
+
+	case liveLogsMsg:
+		if !m.liveMsgCurrent(msg.jobID, msg.gen) {
+			break
+		}
+		if errors.Is(msg.err, errLiveTransient) {
+			// Server hiccup: keep streaming, but switch strategies if it persists.
+			m.liveFetchFailed(msg.err)
+			if m.liveFailedAttempts >= maxLiveAttempts {
+				m.abandonLiveWeb()
+				cmds = append(cmds, m.liveLogCmd())
+			}
+			break
+		}
+		if !msg.endpointOK && msg.err == nil {
+			m.abandonLiveWeb()
+			cmds = append(cmds, m.liveLogCmd())
+			break
+		}
+		if msg.err != nil {
+			m.liveFetchFailed(msg.err)
+			break
+		}
+		m.liveChangeID = msg.nextChangeID
+		m.appendLiveLog(msg.content)
+
+	case blobLogsMsg:
+		if !m.liveMsgCurrent(msg.jobID, msg.gen) {
+			break
+		}
+		if errors.Is(msg.err, errLogsNotReady) {
+			m.waitForLogs()
+			break
+		}
+		if msg.err != nil {
+			m.liveFetchFailed(msg.err)
+			break
+		}
+		m.logBlobURL = msg.url
+		m.logBlobOffset = msg.blob.offset
+		m.logBlobGzip = msg.blob.gzipped
+		if msg.blob.full {
+			m.logRaw = ""
+			m.logBlobPartial = ""
+		}
+		m.appendLiveLog(m.blobLines(msg.blob.content))
 
 	case pipelineInfoMsg:
+		if !m.liveMsgCurrent(msg.jobID, msg.gen) {
+			break
+		}
 		m.pipelineInfo = msg.info
+		if msg.info == nil {
+			m.liveFetchFailed(fmt.Errorf("pipeline service info unavailable"))
+		}
 
 	case stepLogsMsg:
+		if !m.liveMsgCurrent(msg.jobID, msg.gen) {
+			break
+		}
+		if msg.err != nil {
+			m.liveFetchFailed(msg.err)
+			break
+		}
 		if msg.maxFetchedID > m.stepLogsFetched {
 			m.stepLogsFetched = msg.maxFetchedID
 			if msg.content != "" {
-				if m.logRaw != "" {
-					m.logRaw += "\n"
-				}
-				m.logRaw += msg.content
-				m.logLoaded = true
-				m.applyLogFilter()
+				m.appendLiveLog(msg.content)
 			} else if !m.logLoaded {
 				waitingMsg := "Waiting for step logs..."
 				m.logViewport.SetContent(waitingMsg)
@@ -1135,6 +3710,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
+		if m.jobsRefreshing() {
+			m.jobsList.SetDelegate(m.jobDelegate())
+		}
 	}
 
 	// Delegate remaining messages to the active list/viewport.
@@ -1155,6 +3733,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.workflowsList, cmd = m.workflowsList.Update(msg)
 		cmds = append(cmds, cmd)
+	case stateDispatchHistory:
+		var cmd tea.Cmd
+		m.historyList, cmd = m.historyList.Update(msg)
+		cmds = append(cmds, cmd)
+	case stateArtifacts:
+		var cmd tea.Cmd
+		m.artifactsList, cmd = m.artifactsList.Update(msg)
+		cmds = append(cmds, cmd)
+	case stateAnnotations:
+		var cmd tea.Cmd
+		m.annotationsList, cmd = m.annotationsList.Update(msg)
+		cmds = append(cmds, cmd)
 	case stateDispatchForm:
 		// Forward non-key messages (e.g. cursor blink) to the active textinput.
 		if len(m.formFields) > 0 {
@@ -1171,22 +3761,549 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleMouse scrolls the log and overlay viewports with the mouse wheel and
+// moves the selection of lists. Other mouse events are ignored.
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || (msg.Button != tea.MouseButtonWheelUp && msg.Button != tea.MouseButtonWheelDown) {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	switch {
+	case m.showHelp:
+		m.helpViewport, cmd = m.helpViewport.Update(msg)
+		return m, cmd
+	case m.state == stateLogs && m.showSummary:
+		m.summaryViewport, cmd = m.summaryViewport.Update(msg)
+		return m, cmd
+	case m.state == stateLogs && m.showDiff:
+		m.diffViewport, cmd = m.diffViewport.Update(msg)
+		return m, cmd
+	case m.state == stateLogs:
+		if msg.Button == tea.MouseButtonWheelUp {
+			m.scrollLogs(-mouseWheelLines)
+		} else {
+			m.scrollLogs(mouseWheelLines)
+		}
+		return m, nil
+	}
+
+	var l *list.Model
+	switch m.state {
+	case stateRuns:
+		l = &m.runsList
+	case stateJobs:
+		l = &m.jobsList
+	case statePRs:
+		l = &m.prsList
+	case stateWorkflows:
+		l = &m.workflowsList
+	case stateDispatchHistory:
+		l = &m.historyList
+	case stateArtifacts:
+		l = &m.artifactsList
+	case stateAnnotations:
+		l = &m.annotationsList
+	default:
+		return m, nil
+	}
+	if l.SettingFilter() {
+		return m, nil
+	}
+	if msg.Button == tea.MouseButtonWheelUp {
+		l.CursorUp()
+	} else {
+		l.CursorDown()
+	}
+	return m, nil
+}
+
+// mouseWheelLines is how far one wheel notch scrolls the log.
+const mouseWheelLines = 3
+
+// scrollLogs scrolls the log by delta lines. Scrolling up stops following the
+// output; reaching the bottom resumes it.
+func (m *model) scrollLogs(delta int) {
+	m.followStep = false
+	m.logPinned = false
+	maxOffset := max(0, lipgloss.Height(m.logContent)-m.logViewport.Height)
+	if delta < 0 {
+		if m.logViewport.YOffset > 0 {
+			m.logViewport.YOffset = max(0, m.logViewport.YOffset+delta)
+			m.autoScroll = false
+		}
+		return
+	}
+	m.logViewport.YOffset = min(maxOffset, m.logViewport.YOffset+delta)
+	if m.logViewport.YOffset >= maxOffset {
+		m.autoScroll = true
+		m.logViewport.GotoBottom()
+	}
+}
+
+// resizeJobsList fits the jobs list below the optional run metrics panel.
+func (m *model) resizeJobsList() {
+	h := m.height - 4
+	if m.runMetricsShown() {
+		h -= len(m.runMetricsLines())
+	}
+	h -= len(m.pendingReviewLines()) + len(m.scheduleLines())
+	m.jobsList.SetSize(m.mainWidth(), max(1, h))
+	m.jobsList.SetDelegate(m.jobDelegate())
+}
+
+// jobDelegate returns the jobs list delegate for the current width and
+// spinner frame.
+func (m model) jobDelegate() jobDelegate {
+	return jobDelegate{width: m.mainWidth(), spinner: stripANSI(m.spinner.View())}
+}
+
+// jobsRefreshing reports whether any job row has a refresh outstanding.
+func (m model) jobsRefreshing() bool {
+	for _, it := range m.jobsList.Items() {
+		if ji, ok := it.(jobItem); ok && ji.refreshing {
+			return true
+		}
+	}
+	return false
+}
+
+// quit exits the program, first asking for confirmation when enabled in the
+// config and something the user just kicked off is still pending.
+func (m model) quit() (tea.Model, tea.Cmd) {
+	if !m.config.ConfirmQuit {
+		return m, tea.Quit
+	}
+	reason := m.pendingWork()
+	if reason == "" {
+		return m, tea.Quit
+	}
+	m.confirm = &confirmPrompt{message: reason + ". Quit anyway?", onYes: tea.Quit}
+	return m, nil
+}
+
+// pendingWork describes work that would be lost from sight by quitting, or
+// returns "" when nothing is pending.
+func (m model) pendingWork() string {
+	if m.dispatchInFlight {
+		return "A workflow dispatch is in flight"
+	}
+	if m.state == stateJobs || m.state == stateLogs {
+		for _, j := range m.lastJobsForRun[m.selectedRun.ID] {
+			if isRunning(j.Status) {
+				return fmt.Sprintf("Run %q is still in progress", m.selectedRun.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// openSourceLocation opens the file and line an error points at, at the run's
+// head commit, in the browser.
+func (m *model) openSourceLocation(loc sourceLocation) {
+	if m.selectedRun.HeadSHA == "" {
+		m.statusMsg = "Commit SHA not available for this run"
+		return
+	}
+	path := m.client.repoRelativePath(loc.Path)
+	if err := OpenInBrowser(m.client.FileURL(m.selectedRun.HeadSHA, path, loc.Line)); err != nil {
+		m.statusMsg = fmt.Sprintf("error opening browser: %v", err)
+		return
+	}
+	if loc.Line > 0 {
+		path = fmt.Sprintf("%s:%d", path, loc.Line)
+	}
+	m.showSuccess("Opened " + path + " in browser")
+}
+
+// togglePin anchors the log viewport at the ##[group] boundary at or above the
+// top visible line, so streamed output accumulates below without scrolling the
+// step out of view. Pressing it again releases the anchor and resumes
+// auto-scroll.
+func (m *model) togglePin() {
+	if m.logPinned {
+		m.logPinned = false
+		m.autoScroll = true
+		m.logViewport.GotoBottom()
+		return
+	}
+	anchor, name := -1, ""
+	for i, line := range strings.Split(m.logDisplayText(), "\n") {
+		if m.logRow(i) > m.logViewport.YOffset && anchor >= 0 {
+			break
+		}
+		if strings.HasPrefix(line, "##[group]") {
+			anchor, name = i, strings.TrimPrefix(line, "##[group]")
+		}
+	}
+	if anchor < 0 {
+		m.statusMsg = "No step boundary to pin to"
+		return
+	}
+	m.logPinned = true
+	m.logAnchor = anchor
+	m.logAnchorName = name
+	m.followStep = false
+	m.autoScroll = false
+	m.logViewport.SetYOffset(m.logRow(anchor))
+}
+
+// selectNextJob moves the jobs list selection to the next job after the current
+// one that matches, wrapping around, and reports its position among all matches
+// (e.g. "(2 of 5 failed)"). Only jobs shown by the list filter are considered.
+func (m *model) selectNextJob(label string, match func(Job) bool) {
+	items := m.jobsList.VisibleItems()
+	var matches []int
+	for i, item := range items {
+		if ji, ok := item.(jobItem); ok && match(ji.job) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		m.statusMsg = "No " + label + " jobs"
+		return
+	}
+	pos := 0
+	for i, idx := range matches {
+		if idx > m.jobsList.Index() {
+			pos = i
+			break
+		}
+	}
+	m.jobsList.Select(matches[pos])
+	m.statusMsg = fmt.Sprintf("(%d of %d %s)", pos+1, len(matches), label)
+}
+
+// dispatchRef resolves the ref to dispatch on from the ref field: the highlighted
+// branch or tag when a list section is active, otherwise the typed value, falling
+// back to the default branch.
+func (m model) dispatchRef() string {
+	ref := ""
+	if len(m.formFields) > 0 {
+		ref = m.formFields[0].input.Value()
+		if m.formFields[0].fieldType == "ref" {
+			filter := strings.ToLower(ref)
+			switch m.refSection {
+			case 1:
+				if row, ok := m.selectedBranchRow(filter); ok && !row.header {
+					ref = qualifyRef(row.name, "refs/heads/", m.refTags)
+				}
+			case 2:
+				if ft := filterRefs(m.refTags, filter, m.config.RefMatch); len(ft) > 0 {
+					idx := m.refTagIdx
+					if idx >= len(ft) {
+						idx = len(ft) - 1
+					}
+					ref = qualifyRef(ft[idx], "refs/tags/", m.refBranches)
+				}
+			}
+		}
+	}
+	if ref == "" {
+		ref = m.dispatchDefaultRef()
+	}
+	return ref
+}
+
+// branchRows returns the branch list of the ref picker for the lower-cased
+// filter, grouped by prefix when ref_groups is enabled.
+func (m model) branchRows(filter string) []refRow {
+	fb := filterRefs(m.refBranches, filter, m.config.RefMatch)
+	if m.config.RefGroups {
+		return groupRefs(fb, m.refCollapsed, filter != "")
+	}
+	rows := make([]refRow, len(fb))
+	for i, b := range fb {
+		rows[i] = refRow{name: b}
+	}
+	return rows
+}
+
+// selectedBranchRow returns the highlighted row of the branch list, clamped to
+// its end; false when the list is empty.
+func (m model) selectedBranchRow(filter string) (refRow, bool) {
+	rows := m.branchRows(filter)
+	if len(rows) == 0 {
+		return refRow{}, false
+	}
+	return rows[min(m.refBranchIdx, len(rows)-1)], true
+}
+
+// dispatchDefaultRef is the ref the dispatch form starts with: the ref
+// configured for this repo, unless it turned out not to exist, otherwise the
+// default branch.
+func (m model) dispatchDefaultRef() string {
+	if !m.dispatchRefMissing {
+		if ref := m.config.dispatchRef(m.client.host, m.client.owner, m.client.repo); ref != "" {
+			return ref
+		}
+	}
+	if m.defaultBranch != "" {
+		return m.defaultBranch
+	}
+	return "main"
+}
+
+// resetDispatchForm puts the default ref and the YAML defaults back into the
+// form, undoing prefillDispatchForm and any edits.
+func (m *model) resetDispatchForm() {
+	if len(m.formFields) == 0 {
+		return
+	}
+	m.formFields[0].input.SetValue(m.dispatchDefaultRef())
+	for i := 1; i < len(m.formFields); i++ {
+		f := &m.formFields[i]
+		if idx := slices.Index(f.options, f.defaultValue); idx >= 0 {
+			f.optionIdx = idx
+		}
+		f.area = nil
+		f.input.SetValue(f.defaultValue)
+	}
+}
+
+// toggleExpandedField switches the active string field between its single-line
+// input and a multi-line editor, e.g. for JSON or config inputs.
+func (m *model) toggleExpandedField() {
+	if m.formButton != 0 || len(m.formFields) == 0 {
+		return
+	}
+	f := &m.formFields[m.formActiveField]
+	switch {
+	case f.fieldType != "string":
+		m.statusMsg = "Only text inputs can be expanded"
+	case f.area == nil:
+		f.expand(f.input.Value())
+		m.statusMsg = ""
+	case !f.collapse():
+		m.statusMsg = "Remove the line breaks to collapse the field"
+	default:
+		m.statusMsg = ""
+	}
+}
+
+// updateArea passes msg to the active field's multi-line editor.
+func (m *model) updateArea(msg tea.KeyMsg) tea.Cmd {
+	f := &m.formFields[m.formActiveField]
+	var cmds []tea.Cmd
+	if !f.area.Focused() {
+		cmds = append(cmds, f.area.Focus())
+	}
+	area, cmd := f.area.Update(msg)
+	*f.area = area
+	f.fitArea()
+	return tea.Batch(append(cmds, cmd)...)
+}
+
+// envVarsCmd fetches the variables of env for the dispatch form's environment
+// hint, once per environment. Unreadable variables are never retried.
+func (m *model) envVarsCmd(env string) tea.Cmd {
+	if env == "" || !slices.Contains(m.dispatchEnvs(), env) {
+		return nil
+	}
+	if _, requested := m.envVars[env]; requested {
+		return nil
+	}
+	m.envVars[env] = nil
+	return fetchEnvVarsCmd(m.client, env)
+}
+
+// dispatchEnvs returns the repository's environments once loaded for the
+// dispatch form.
+func (m model) dispatchEnvs() []string {
+	for _, f := range m.formFields {
+		if f.fieldType == "environment" {
+			return f.options
+		}
+	}
+	return nil
+}
+
+// dispatchInputs collects the non-empty input values from the dispatch form.
+func (m model) dispatchInputs() map[string]string {
+	inputs := make(map[string]string)
+	if len(m.formFields) == 0 {
+		return inputs
+	}
+	for _, f := range m.formFields[1:] {
+		if val := f.value(); val != "" {
+			inputs[f.label] = val
+		}
+	}
+	return inputs
+}
+
+// dispatchCommand is the gh CLI command equivalent to dispatching the form as
+// it is filled in, with inputs in form order.
+func (m model) dispatchCommand() string {
+	repo := m.client.owner + "/" + m.client.repo
+	if m.client.host != "github.com" {
+		repo = m.client.host + "/" + repo
+	}
+	args := []string{"gh", "workflow", "run", filepath.Base(m.selectedWorkflow.Path), "--repo", repo, "--ref", m.dispatchRef()}
+	// The values come from dispatchInputs so the command matches what a
+	// dispatch would send; the input fields only give their order.
+	inputs := m.dispatchInputs()
+	if len(m.formFields) > 1 {
+		for _, f := range m.formFields[1:] {
+			if val, ok := inputs[f.label]; ok {
+				args = append(args, "-f", f.label+"="+val)
+			}
+		}
+	}
+	for i, a := range args {
+		args[i] = shellQuote(a)
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell unless it is made of safe characters only.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// copyDispatchCommand copies dispatchCommand to the clipboard instead of
+// dispatching.
+func (m *model) copyDispatchCommand() {
+	command := m.dispatchCommand()
+	if err := clipboard.WriteAll(command); err != nil {
+		m.statusMsg = fmt.Sprintf("error copying command: %v", err)
+		return
+	}
+	m.showSuccess("Copied: " + command)
+}
+
+// openRun switches to the jobs view for run.
+func (m *model) openRun(run WorkflowRun) tea.Cmd {
+	if run.ID != m.selectedRun.ID {
+		m.jobsList.ResetFilter() // a job name filter is specific to its run
+	}
+	m.selectedRun = run
+	m.prJobsRuns = nil
+	m.completingRuns = nil
+	m.state = stateJobs
+	m.loading = true
+	m.statusMsg = ""
+	m.jobsPolling = true
+	return tea.Batch(fetchJobsCmd(m.client, run.ID), m.jobsPollCmd(), m.runTimingCmd(), m.runScheduleCmd())
+}
+
+// runScheduleCmd looks up the cron entry behind the selected run when it was
+// triggered by a schedule, once per run.
+func (m *model) runScheduleCmd() tea.Cmd {
+	if m.selectedRun.Event != "schedule" || m.runScheduleRunID == m.selectedRun.ID {
+		return nil
+	}
+	m.runScheduleRunID = m.selectedRun.ID
+	m.runSchedule = nil
+	return fetchRunScheduleCmd(m.client, m.selectedRun)
+}
+
+// openLogs switches to the log view for job, resetting all per-job log state.
+func (m *model) openLogs(job Job) tea.Cmd {
+	for _, r := range m.prJobsRuns {
+		if r.ID == job.RunID {
+			m.selectedRun = r
+		}
+	}
+	m.selectedJob = job
+	m.state = stateLogs
+	m.jobsPolling = false
+	m.logContent = ""
+	m.logRaw = ""
+	m.logTimestamped = ""
+	m.lastLogLength = 0
+	m.logLoaded = false
+	m.autoScroll = true
+	m.statusMsg = ""
+	m.logFilter = ""
+	m.logFilterMode = false
+	m.logFiltered = false
+	m.logSearch = ""
+	m.logSearchMode = false
+	m.followStep = false
+	m.logPinned = false
+	m.showSummary = false
+	m.showDiff = false
+	m.errorIdx = -1
+	m.foldedGroups = map[int]bool{}
+	m.groupCursor = -1
+	m.groupLines = nil
+	m.resetLiveState()
+	m.updateSizes()
+	if isRunning(job.Status) {
+		return tea.Batch(fetchJobsCmd(m.client, m.selectedRun.ID), m.logPollCmd(), m.liveLogCmd())
+	}
+	if cached, ok := m.logCache.get(job.ID); ok {
+		return func() tea.Msg { return newLogsLoadedMsg(job.ID, false, cached) }
+	}
+	return fetchLogsCmd(m.client, job)
+}
+
+// setSummaryContent renders the job summary into its viewport.
+func (m *model) setSummaryContent() {
+	if m.summaryText == "" {
+		m.summaryViewport.SetContent("\n  " + styleDim.Render("No summary for this job"))
+		return
+	}
+	m.summaryViewport.SetContent(renderMarkdown(m.config.Redact.applyLines(m.summaryText), m.summaryViewport.Width))
+}
+
+// setDiffContent renders the log comparison into its viewport.
+func (m *model) setDiffContent() {
+	content, hunks := renderLogDiff(m.diffRows, m.diffViewport.Width, m.config.Redact)
+	m.diffViewport.SetContent(content)
+	m.diffHunks = hunks
+}
+
+// nextDiff scrolls the comparison to the next (or, when delta is negative,
+// previous) block of differences, wrapping around.
+func (m *model) nextDiff(delta int) {
+	n := len(m.diffHunks)
+	if n == 0 {
+		return
+	}
+	m.diffHunkIdx = ((m.diffHunkIdx+delta)%n + n) % n
+	m.diffViewport.SetYOffset(max(0, m.diffHunks[m.diffHunkIdx]-3))
+}
+
 // updateSizes resizes the log viewport to fit the current terminal dimensions.
 func (m *model) updateSizes() {
 	extra := 0
-	if m.logFilterMode {
+	if m.logFilterMode || m.logSearchMode {
 		extra = 1
 	}
+	if m.compactHeader {
+		extra--
+	}
 	h := max(1, m.height-4-extra)
-	savedOffset := m.logViewport.YOffset
-	m.logViewport.Width = m.width
+	// Rewrapping moves lines to other rows; keep the top line in place.
+	savedLine := m.logLineAt(m.logViewport.YOffset)
+	m.logViewport.Width = m.mainWidth()
 	m.logViewport.Height = h
+	m.summaryViewport.Width = m.mainWidth()
+	m.summaryViewport.Height = h
+	if m.summaryLoaded {
+		m.setSummaryContent()
+	}
+	m.diffViewport.Width = m.mainWidth()
+	m.diffViewport.Height = max(1, h-1) // below the column header
+	if m.diffLoaded {
+		m.setDiffContent()
+	}
 	if m.logContent != "" {
-		m.logViewport.SetContent(m.logContent)
+		if m.wrapEnabled && m.logLoaded {
+			m.renderLogContent() // the wrap width follows the viewport
+		} else {
+			m.logViewport.SetContent(m.logContent)
+		}
 		if m.autoScroll {
 			m.logViewport.GotoBottom()
 		} else {
-			m.logViewport.YOffset = savedOffset
+			// A taller viewport shows more lines at once, so the old offset
+			// may now scroll past the end; SetYOffset clamps it.
+			m.logViewport.SetYOffset(m.logRow(savedLine))
 		}
 	}
 }
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUpdateSizesClampsLogOffset(t *testing.T) {
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	content := strings.Join(lines, "\n")
+
+	// Growing the viewport from 10 to 20 rows leaves room to scroll to row 30.
+	tests := []struct {
+		name   string
+		offset int
+		want   int
+	}{
+		{"top", 0, 0},
+		{"inside", 20, 20},
+		{"last line", len(lines) - 1, len(lines) - 20},
+		{"past the end", len(lines) + 10, len(lines) - 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{width: 80, height: 24, sidebarHidden: true, logContent: content}
+			m.logViewport = viewport.New(80, 10)
+			m.logViewport.SetContent(content)
+			m.logViewport.YOffset = tt.offset
+			m.updateSizes()
+			if m.logViewport.Height != 20 {
+				t.Fatalf("viewport height = %d, want 20", m.logViewport.Height)
+			}
+			if got := m.logViewport.YOffset; got != tt.want {
+				t.Errorf("YOffset = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlobLinesJoinsRanges(t *testing.T) {
+	var m model
+	var got []string
+	for _, chunk := range []string{
+		"2024-01-01T00:00:00.0000000Z first\n2024-01-01T00:00:01.00",
+		"00000Z sec",
+		"ond\n",
+		"third",
+	} {
+		if lines := m.blobLines(chunk); lines != "" {
+			got = append(got, lines)
+		}
+	}
+	if want := []string{"first", "second"}; !slices.Equal(got, want) {
+		t.Errorf("complete lines = %q, want %q", got, want)
+	}
+	if m.logBlobPartial != "third" {
+		t.Errorf("partial line = %q, want %q", m.logBlobPartial, "third")
+	}
+}
+
+func TestNoFailingJobStatus(t *testing.T) {
+	run := func(status, conclusion string) WorkflowRun {
+		return WorkflowRun{Name: "CI", Status: status, Conclusion: conclusion}
+	}
+	tests := []struct {
+		name string
+		runs []WorkflowRun
+		want string
+	}{
+		{"no runs", nil, "No checks have run on this PR's head commit"},
+		{"queued", []WorkflowRun{run("completed", "success"), run("queued", "")}, "No failures yet; some checks are still queued or running"},
+		{"pending", []WorkflowRun{run("pending", "")}, "No failures yet; some checks are still queued or running"},
+		{"cancelled", []WorkflowRun{run("completed", "cancelled")}, "CI ended cancelled without a failed job"},
+		{"passing", []WorkflowRun{run("completed", "success"), run("completed", "skipped")}, ""},
+	}
+	for _, tt := range tests {
+		if got := noFailingJobStatus(tt.runs); got != tt.want {
+			t.Errorf("%s: noFailingJobStatus = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAnyRunning(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"in_progress", true},
+		{"queued", true},
+		{"waiting", true},
+		{"pending", true},
+		{"requested", true},
+		{"completed", false},
+	}
+	for _, tt := range tests {
+		jobs := []Job{{Status: "completed"}, {Status: tt.status}}
+		if got := anyRunning(jobs); got != tt.want {
+			t.Errorf("anyRunning with a %s job = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRunsLoadedDropsStaleSources(t *testing.T) {
+	m := model{
+		state:       stateRuns,
+		runsList:    list.New(nil, runDelegate{width: 80}, 80, 20),
+		progressIn:  make(map[int64]bool),
+		runProgress: make(map[int64]jobProgress),
+	}
+	runs := []WorkflowRun{{ID: 1, Name: "CI", Status: "completed", Conclusion: "success"}}
+
+	// A branch search answered after the user went back to all runs.
+	next, _ := m.Update(runsLoadedMsg{runs: runs, branch: "feature"})
+	m = next.(model)
+	if n := len(m.runsList.Items()); n != 0 {
+		t.Fatalf("stale branch runs were shown: %d items", n)
+	}
+
+	next, _ = m.Update(runsLoadedMsg{runs: runs})
+	m = next.(model)
+	if n := len(m.runsList.Items()); n != 1 {
+		t.Fatalf("runs list has %d items, want 1", n)
+	}
+}
+
+func TestRunCompletionWaitsForRun(t *testing.T) {
+	m := model{
+		state:          stateJobs,
+		selectedRun:    WorkflowRun{ID: 7, Status: "in_progress"},
+		jobsList:       list.New(nil, jobDelegate{}, 80, 20),
+		logCache:       newLogCache(0),
+		logPrefetching: make(map[int64]bool),
+		lastJobsForRun: map[int64][]Job{7: {{ID: 1, RunID: 7, Name: "build", Status: "in_progress"}}},
+	}
+	update := func(msg tea.Msg) {
+		t.Helper()
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+
+	// build finished, but deploy (needs: build) hasn't been created yet.
+	update(jobsLoadedMsg{{ID: 1, RunID: 7, Name: "build", Status: "completed", Conclusion: "success"}})
+	update(runRefreshedMsg(WorkflowRun{ID: 7, Status: "in_progress"}))
+	if m.statusMsg != "" {
+		t.Fatalf("status = %q while the run is still in progress", m.statusMsg)
+	}
+
+	// deploy ran and failed with continue-on-error; the run succeeded.
+	jobs := []Job{
+		{ID: 1, RunID: 7, Name: "build", Status: "completed", Conclusion: "success"},
+		{ID: 2, RunID: 7, Name: "deploy", Status: "completed", Conclusion: "failure"},
+	}
+	update(jobsLoadedMsg(jobs))
+	update(runRefreshedMsg(WorkflowRun{ID: 7, Status: "completed", Conclusion: "success"}))
+	if want := watchSummary(WorkflowRun{Status: "completed", Conclusion: "success"}, jobs); m.statusMsg != want {
+		t.Errorf("status = %q, want %q", m.statusMsg, want)
+	}
+	if len(m.completingRuns) != 0 {
+		t.Errorf("run still awaited after completing: %v", m.completingRuns)
+	}
+}
+
+func TestLateLogOfLeftJobIsOnlyCached(t *testing.T) {
+	jobA := Job{ID: 1, Name: "a", Status: "completed", Conclusion: "success"}
+	jobB := Job{ID: 2, Name: "b", Status: "completed", Conclusion: "success"}
+	m := model{
+		width: 80, height: 24, sidebarHidden: true,
+		jobsList:       list.New(nil, jobDelegate{}, 80, 20),
+		logViewport:    viewport.New(80, 20),
+		logCache:       newLogCache(1 << 20),
+		logPrefetching: make(map[int64]bool),
+		lastJobsForRun: make(map[int64][]Job),
+	}
+	m.logCache.put(jobB.ID, "log of b")
+
+	// Open A, go back and open B from the cache before A's log arrives.
+	m.openLogs(jobA)
+	cmd := m.openLogs(jobB)
+	next, _ := m.Update(cmd())
+	m = next.(model)
+	if m.logRaw != "log of b" {
+		t.Fatalf("logRaw = %q, want B's log", m.logRaw)
+	}
+
+	next, _ = m.Update(newLogsLoadedMsg(jobA.ID, true, "log of a"))
+	m = next.(model)
+	if m.logRaw != "log of b" {
+		t.Errorf("logRaw = %q after A's late log, want B's log", m.logRaw)
+	}
+	if got, ok := m.logCache.get(jobA.ID); !ok || got != "log of a" {
+		t.Errorf("A's completed log was not cached: %q, %v", got, ok)
+	}
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // ─── Top-level View dispatcher ────────────────────────────────────────────────
@@ -14,6 +17,18 @@ func (m model) View() string {
 	if m.width == 0 {
 		return ""
 	}
+	if m.confirm != nil {
+		return m.viewConfirm()
+	}
+	if m.review != nil {
+		return m.viewReview()
+	}
+	if m.prComment != nil {
+		return m.viewPRComment()
+	}
+	if m.showHelp {
+		return m.viewHelp()
+	}
 	switch m.state {
 	case stateMenu:
 		return m.viewMenu()
@@ -29,6 +44,12 @@ func (m model) View() string {
 		return m.viewWorkflows()
 	case stateDispatchForm:
 		return m.viewDispatchForm()
+	case stateDispatchHistory:
+		return m.viewDispatchHistory()
+	case stateArtifacts:
+		return m.viewArtifacts()
+	case stateAnnotations:
+		return m.viewAnnotations()
 	}
 	return ""
 }
@@ -38,6 +59,14 @@ func (m model) View() string {
 func (m model) renderAppBar(viewName string) string {
 	left := appNameStyle.Render("tgh")
 	right := " " + m.client.owner + "/" + m.client.repo + " "
+	if m.config.ServerTime {
+		if now, ok := m.client.ServerTime(); ok {
+			right = " server " + now.Local().Format("15:04:05") + " │" + right
+		}
+	}
+	if rate := m.rateLimitIndicator(); rate != "" {
+		right = " " + rate + " │" + right
+	}
 
 	usedWidth := lipgloss.Width(left) + lipgloss.Width(viewName) + lipgloss.Width(right)
 	gap := max(0, m.width-usedWidth)
@@ -63,6 +92,41 @@ func renderFooter(hints []string) string {
 	return footerStyle.Render(" " + strings.Join(parts, styleDim.Render("  ")))
 }
 
+// ─── Confirmation overlay ─────────────────────────────────────────────────────
+
+// viewConfirm renders the pending confirmation prompt centred on screen.
+func (m model) viewConfirm() string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAmber).
+		Padding(1, 3).
+		Render(styleHeader.Render(m.confirm.message) + "\n\n" +
+			renderFooter([]string{"<y/enter> yes", "<n/esc> no"}))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// legendEntries lists every status the app distinguishes, in the order shown
+// in the legend. Icons come from statusIcon so the legend cannot drift.
+var legendEntries = []struct{ status, conclusion, label string }{
+	{"completed", "success", "success"},
+	{"completed", "failure", "failure"},
+	{"in_progress", "", "running"},
+	{"queued", "", "queued"},
+	{"completed", "cancelled", "cancelled"},
+	{"completed", "skipped", "skipped"},
+}
+
+// renderLegend explains the status icons and the step dots of completed jobs.
+func renderLegend() string {
+	parts := make([]string, 0, len(legendEntries)+1)
+	for _, e := range legendEntries {
+		parts = append(parts, statusIcon(e.status, e.conclusion)+styleDim.Render(" "+e.label))
+	}
+	dots := statusSuccess.Render(icons.stepDone) + statusFailure.Render(icons.stepDone) + statusNeutral.Render(icons.stepDone) + styleDim.Render(icons.stepPending)
+	parts = append(parts, dots+styleDim.Render(" steps: passed/failed/other/pending"))
+	return strings.Join(parts, "  ")
+}
+
 // ─── Menu view ────────────────────────────────────────────────────────────────
 
 var menuItems = []struct {
@@ -77,23 +141,27 @@ func (m model) viewMenu() string {
 	appBar := m.renderAppBar("Menu")
 
 	var sb strings.Builder
-	sb.WriteString("\n")
+	if m.statusMsg != "" {
+		sb.WriteString(styleDim.Render(" "+m.statusMsg) + "\n")
+	} else {
+		sb.WriteString("\n")
+	}
 	for i, item := range menuItems {
 		var line string
 		if i == m.menuIndex {
-			bg := lipgloss.Color("63")
+			bg := colorSelected
 			bgPlain := lipgloss.NewStyle().Background(bg)
 			prefix := bgPlain.Render(" ▶ ")
-			name := lipgloss.NewStyle().Background(bg).Foreground(lipgloss.Color("15")).Bold(true).Width(22).Render(item.name)
+			name := lipgloss.NewStyle().Background(bg).Foreground(colorWhite).Bold(true).Width(22).Render(item.name)
 			sep := bgPlain.Render("  ")
-			desc := lipgloss.NewStyle().Background(bg).Foreground(lipgloss.Color("245")).Render(item.desc)
+			desc := lipgloss.NewStyle().Background(bg).Foreground(colorDimText).Render(item.desc)
 			line = prefix + name + sep + desc
 			// Pad to full terminal width so the highlight spans the whole row.
 			if vis := lipgloss.Width(line); vis < m.width {
 				line += bgPlain.Render(strings.Repeat(" ", m.width-vis))
 			}
 		} else {
-			nameCol := lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Width(22).Render(item.name)
+			nameCol := lipgloss.NewStyle().Foreground(colorWhite).Width(22).Render(item.name)
 			line = "   " + nameCol + "  " + styleDim.Render(item.desc)
 		}
 		sb.WriteString(line + "\n")
@@ -101,15 +169,15 @@ func (m model) viewMenu() string {
 
 	// Pad remaining space
 	used := 1 + len(menuItems) + 2 // appbar + blank + items + footer
+	footer := renderFooter(keyHints(menuKeys, "↑/↓", "enter", "/", "W", "?", "q"))
+	if m.branchSearchMode {
+		used++
+		footer = filterBarStyle.Width(m.width).Render("  branch: "+m.branchSearch+styleAccent.Render("█")) + "\n" +
+			renderFooter([]string{"<enter> list runs", "<esc> cancel"})
+	}
 	remaining := max(0, m.height-used)
 	sb.WriteString(strings.Repeat("\n", remaining))
 
-	footer := renderFooter([]string{
-		"<↑/↓> navigate",
-		"<enter> open",
-		"<q> quit",
-	})
-
 	return lipgloss.JoinVertical(lipgloss.Left,
 		appBar,
 		sb.String(),
@@ -132,28 +200,28 @@ func (m model) viewRuns() string {
 	if m.statusMsg != "" {
 		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
 	} else if m.selectedPR != nil {
-		prLabel := truncate(fmt.Sprintf("#%d %s", m.selectedPR.Number, m.selectedPR.Title), m.width-30)
+		rollup := m.checksRollupLabel()
+		prLabel := truncate(fmt.Sprintf("#%d %s", m.selectedPR.Number, m.selectedPR.Title), m.width-30-lipgloss.Width(rollup))
 		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(
-			" Pull Requests › " + prLabel + " › Runs",
+			" Pull Requests › " + prLabel + " › Runs" + m.runFilterLabel() + rollup,
 		)
+	} else if m.branchFilter != "" {
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Actions › Branch " + truncate(m.branchFilter, m.width-30) + " › Runs" + m.runFilterLabel())
 	} else {
-		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Actions › Runs")
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Actions › Runs" + m.runFilterLabel())
 	}
 
 	colHeaders := m.runColHeaders()
 	listView := m.runsList.View()
 
-	footerHints := []string{
-		"<enter> open",
-		"<r> rerun-failed",
-		"<R> rerun-all",
-		"<d> dispatch",
-		"<o> browser",
-		"<tab> refresh",
-		"<esc/b> back",
-		"<q> quit",
+	footerKeys := []string{"enter", "r", "R", "l", "x", "a", "d", "f", "o", "Y", "tab", "?", "esc/b", "q"}
+	if item, ok := m.runsList.SelectedItem().(runItem); ok {
+		footerKeys = m.withoutBlockedReruns(item.run, footerKeys)
 	}
-	footer := renderFooter(footerHints)
+	if m.selectedPR != nil {
+		footerKeys = append([]string{"J", "P/C"}, footerKeys...)
+	}
+	footer := renderFooter(keyHints(runsKeys, footerKeys...))
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		appBar,
@@ -164,25 +232,46 @@ func (m model) viewRuns() string {
 	)
 }
 
+// checksRollupLabel summarizes the PR's overall check state for the breadcrumb,
+// or returns "" until the rollup for the PR's head commit has been fetched.
+func (m model) checksRollupLabel() string {
+	if m.selectedPR == nil {
+		return ""
+	}
+	r, ok := m.prChecks[m.selectedPR.Head.SHA]
+	if !ok {
+		return ""
+	}
+	if r.Total == 0 {
+		return "  " + styleDim.Render("no checks")
+	}
+	switch r.State() {
+	case "failure":
+		return "  " + statusFailure.Render(fmt.Sprintf("%s %d of %d checks failing", icons.failure, r.Failed, r.Total))
+	case "pending":
+		return "  " + statusInProgress.Render(fmt.Sprintf("%s %d of %d checks pending", icons.running, r.Pending, r.Total))
+	case "cancelled":
+		return "  " + statusNeutral.Render(fmt.Sprintf("%s %d of %d checks cancelled or skipped", icons.cancelled, r.Cancelled, r.Total))
+	default:
+		return "  " + statusSuccess.Render(fmt.Sprintf("%s all %d checks passing", icons.success, r.Total))
+	}
+}
+
 func (m model) runColHeaders() string {
-	const (
-		cursorW = 2
-		iconW   = 2
-		branchW = 22
-		eventW  = 11
-		ageW    = 8
-		gaps    = 4
-	)
-	nameW := max(8, m.width-cursorW-iconW-branchW-eventW-ageW-gaps)
+	ageW := m.config.TimeFormat.width()
+	durationW, actorW := runOptionalColumns(m.width, m.config.TimeFormat)
+	nameW := max(8, m.width-runCursorW-runIconW-runBranchW-actorW-runEventW-durationW-ageW-runGaps)
 
-	cursor := lipgloss.NewStyle().Width(cursorW).Render("")
-	icon := lipgloss.NewStyle().Width(iconW + 1).Render("")
+	cursor := lipgloss.NewStyle().Width(runCursorW).Render("")
+	icon := lipgloss.NewStyle().Width(runIconW + 1).Render("")
 	name := lipgloss.NewStyle().Width(nameW).Render("NAME")
-	branch := lipgloss.NewStyle().Width(branchW).Render("BRANCH")
-	event := lipgloss.NewStyle().Width(eventW).Render("EVENT")
+	branch := lipgloss.NewStyle().Width(runBranchW).Render("BRANCH")
+	event := lipgloss.NewStyle().Width(runEventW).Render("EVENT")
+	actor := optionalCell("ACTOR", actorW)
+	duration := optionalCell("DURATION", durationW)
 	age := lipgloss.NewStyle().Width(ageW).Render("AGE")
 
-	return colHeaderStyle.Render(cursor + icon + name + " " + branch + " " + event + " " + age)
+	return colHeaderStyle.Render(cursor + icon + name + " " + branch + " " + actor + event + " " + duration + age)
 }
 
 // ─── Jobs view ────────────────────────────────────────────────────────────────
@@ -204,31 +293,107 @@ func (m model) viewJobs() string {
 		var prefix string
 		if m.selectedPR != nil {
 			prefix = fmt.Sprintf(" Pull Requests › #%d › Runs › ", m.selectedPR.Number)
+		} else if m.branchFilter != "" {
+			prefix = " Actions › Branch " + truncate(m.branchFilter, 30) + " › Runs › "
 		} else {
 			prefix = " Actions › Runs › "
 		}
-		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(prefix + runLabel)
+		text := prefix + runLabel
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(text + runOrigin(m.selectedRun, m.width-lipgloss.Width(text)-1))
+	}
+
+	jobs := m.jobsList.View()
+	if m.runHasNoJobs() {
+		jobs = lipgloss.NewStyle().Height(m.jobsList.Height()).Render(
+			"\n  " + styleDim.Render("This run produced no jobs (skipped by filters or concurrency)"))
+	}
+	body := m.withSidebar(lipgloss.JoinVertical(lipgloss.Left, m.jobColHeaders(), jobs))
+
+	footerKeys := m.withoutBlockedReruns(m.jobsViewRun(),
+		[]string{"enter", "/", "u", "f/p", "i", "E", "c", "A", "L", "s", "o", "Y", "r", "R", "J", "l", "x", "a", "?", "esc/b", "q"})
+	if item, ok := m.jobsList.SelectedItem().(jobItem); !ok || m.jobRerunBlocker(item.job) != "" {
+		footerKeys = slices.DeleteFunc(footerKeys, func(k string) bool { return k == "J" })
 	}
+	footer := renderFooter(keyHints(jobsKeys, footerKeys...))
 
-	colHeaders := m.jobColHeaders()
-	listView := m.jobsList.View()
+	parts := []string{appBar, breadcrumb}
+	parts = append(parts, m.scheduleLines()...)
+	parts = append(parts, m.pendingReviewLines()...)
+	if m.runMetricsShown() {
+		parts = append(parts, m.runMetricsLines()...)
+	}
+	parts = append(parts, body, footer)
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
 
-	footer := renderFooter([]string{
-		"<enter> logs",
-		"<o> open",
-		"<r> rerun-failed",
-		"<R> rerun-all",
-		"<esc/b> back",
-		"<q> quit",
+// runOrigin describes who triggered a run and the subject of its commit, e.g.
+// " · @octocat · Fix flaky test", cut to width; "" when there is no room.
+func runOrigin(run WorkflowRun, width int) string {
+	var parts []string
+	if run.Actor.Login != "" {
+		parts = append(parts, "@"+run.Actor.Login)
+	}
+	if subject := firstLine(run.HeadCommit.Message); subject != "" {
+		parts = append(parts, subject)
+	}
+	if len(parts) == 0 || width < 12 {
+		return ""
+	}
+	return truncate(" · "+strings.Join(parts, " · "), width)
+}
+
+// withoutBlockedReruns drops the rerun keys from footer keys when run can't be
+// re-run that way, so the footer only offers what will work.
+func (m model) withoutBlockedReruns(run WorkflowRun, keys []string) []string {
+	failedBlocked := m.rerunBlocker(run, true) != ""
+	allBlocked := m.rerunBlocker(run, false) != ""
+	return slices.DeleteFunc(keys, func(k string) bool {
+		return (k == "r" && failedBlocked) || (k == "R" && allBlocked)
 	})
+}
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		appBar,
-		breadcrumb,
-		colHeaders,
-		listView,
-		footer,
-	)
+// scheduleLines shows the cron entry that most likely triggered a scheduled
+// run, and how late the run started.
+func (m model) scheduleLines() []string {
+	s := m.runSchedule
+	if s == nil || m.runScheduleRunID != m.selectedRun.ID || m.state != stateJobs {
+		return nil
+	}
+	line := " " + styleDim.Render("⏱ schedule ") + styleAccent.Render(s.Cron)
+	if !s.Fired.IsZero() {
+		detail := "due " + s.Fired.Format("15:04") + " UTC"
+		if late := m.selectedRun.CreatedAt.Sub(s.Fired).Round(time.Minute); late >= time.Minute {
+			detail += fmt.Sprintf(", started %s late", strings.TrimSuffix(late.String(), "0s"))
+		}
+		line += styleDim.Render(" · " + detail)
+	}
+	if s.Count > 1 {
+		line += styleDim.Render(fmt.Sprintf(" · best match of %d schedules", s.Count))
+	}
+	return []string{line}
+}
+
+// pendingReviewLines shows the selected run's deployments waiting for required
+// reviewers, and whether the current user can approve them.
+func (m model) pendingReviewLines() []string {
+	if m.pendingRunID != m.selectedRun.ID || m.state != stateJobs {
+		return nil
+	}
+	var lines []string
+	for _, d := range m.pendingDeploys {
+		who := "any reviewer"
+		if names := d.ReviewerNames(); len(names) > 0 {
+			who = strings.Join(names, ", ")
+		}
+		verdict := styleDim.Render("you are not a required reviewer")
+		if d.CurrentUserCanApprove {
+			verdict = styleAccent.Render("you can review") + styleDim.Render(" (a)")
+		}
+		line := " " + styleWarn.Render("⏸ "+d.Environment.Name) + styleDim.Render(" awaiting review by ") +
+			truncate(who, max(10, m.width/2)) + styleDim.Render(" · ") + verdict
+		lines = append(lines, line)
+	}
+	return lines
 }
 
 func (m model) jobColHeaders() string {
@@ -239,7 +404,7 @@ func (m model) jobColHeaders() string {
 		durationW = 10
 		gaps      = 3
 	)
-	nameW := max(8, m.width-cursorW-iconW-statusW-durationW-gaps)
+	nameW := max(8, m.mainWidth()-cursorW-iconW-statusW-durationW-gaps)
 
 	cursor := lipgloss.NewStyle().Width(cursorW).Render("")
 	icon := lipgloss.NewStyle().Width(iconW + 1).Render("")
@@ -262,22 +427,18 @@ func (m model) viewPRs() string {
 	appBar := m.renderAppBar(viewLabel)
 
 	var breadcrumb string
-	if m.statusMsg != "" {
+	if m.statusMsg != "" && m.loading {
+		breadcrumb = styleDim.Width(m.width).Render(" " + m.spinner.View() + " " + m.statusMsg)
+	} else if m.statusMsg != "" {
 		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
 	} else {
-		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Pull Requests")
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Pull Requests" + m.prFilterLabel())
 	}
 
 	colHeaders := m.prColHeaders()
 	listView := m.prsList.View()
 
-	footer := renderFooter([]string{
-		"<enter> open runs",
-		"<o> browser",
-		"<r/tab> refresh",
-		"<esc/b> back",
-		"<q> quit",
-	})
+	footer := renderFooter(keyHints(prKeys, "enter", "f", "/", "M", "v", "m", "o", "r/tab", "?", "esc/b", "q"))
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		appBar,
@@ -294,9 +455,9 @@ func (m model) prColHeaders() string {
 		numW    = 6
 		branchW = 18
 		authorW = 14
-		ageW    = 8
 		gaps    = 4
 	)
+	ageW := m.config.TimeFormat.width()
 	titleW := max(8, m.width-cursorW-numW-branchW-authorW-ageW-gaps)
 
 	num := lipgloss.NewStyle().Width(numW).Render("#")
@@ -305,25 +466,81 @@ func (m model) prColHeaders() string {
 	author := lipgloss.NewStyle().Width(authorW).Render("AUTHOR")
 	age := lipgloss.NewStyle().Width(ageW).Render("AGE")
 
-	// Align to match formatPRRow: "    " (4 spaces) + num + " " + title + ...
+	// Align to match formatPRRow: cursor, checks icon and gap (4 columns) + num + " " + title + ...
 	return colHeaderStyle.Render("     " + num + " " + title + " " + branch + " " + author + " " + age)
 }
 
 // ─── Dispatch form view ───────────────────────────────────────────────────────
 
+// renderDispatchPreview renders exactly what Build will send: the resolved ref
+// and the final inputs, marking which values are defaults and which were set.
+func (m model) renderDispatchPreview() string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString("  " + styleHeader.Render("Dispatch preview") + "\n\n")
+	sb.WriteString("  " + styleDim.Render(padRight("workflow", 10)) + m.selectedWorkflow.Name +
+		styleDim.Render("  ("+m.selectedWorkflow.Path+")") + "\n")
+	sb.WriteString("  " + styleDim.Render(padRight("ref", 10)) + styleAccent.Render(m.dispatchRef()) + "\n")
+
+	if len(m.formFields) > 1 {
+		sb.WriteString("  " + styleDim.Render("inputs") + "\n")
+		nameW := 0
+		for _, f := range m.formFields[1:] {
+			nameW = max(nameW, lipgloss.Width(f.label))
+		}
+		for _, f := range m.formFields[1:] {
+			val := f.value()
+			var note string
+			switch {
+			case val == "" && f.required:
+				note = styleError.Render("required, empty — not sent")
+			case val == "":
+				note = styleDim.Render("empty — not sent")
+			case val == f.defaultValue:
+				note = styleDim.Render("default")
+			default:
+				note = styleAccent.Render("set")
+			}
+			if n := strings.Count(val, "\n") + 1; n > 1 {
+				val = fmt.Sprintf("%s … (%d lines)", firstLine(val), n)
+			}
+			sb.WriteString("    " + padRight(f.label, nameW) + " = " + padRight(val, 20) + "  " + note + "\n")
+		}
+	} else {
+		sb.WriteString("  " + styleDim.Render("inputs    (none)") + "\n")
+	}
+	sb.WriteString("\n")
+
+	btnFocus := lipgloss.NewStyle().Background(colorSelected).Foreground(colorWhite).Bold(true)
+	sb.WriteString("  " + styleDim.Render("  Back  ") + "   " + btnFocus.Render(focusMark("  Build  ")) + "\n")
+	return sb.String()
+}
+
 func (m model) viewDispatchForm() string {
 	name := m.selectedWorkflow.Name
 	appBar := m.renderAppBar("Dispatch › " + truncate(name, m.width-20))
 
 	var breadcrumb string
-	if m.loading {
-		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
+	if m.loading || m.statusMsg != "" {
+		breadcrumb = styleDim.Width(m.width).Render(" " + truncate(m.statusMsg, m.width-2))
 	} else {
 		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(
 			" Actions › Runs › Dispatch › " + truncate(name, m.width-35),
 		)
 	}
 
+	if m.dispatchPreview {
+		content := m.renderDispatchPreview()
+		remaining := max(0, m.height-3-strings.Count(content, "\n"))
+		content += strings.Repeat("\n", remaining)
+		return lipgloss.JoinVertical(lipgloss.Left,
+			appBar,
+			breadcrumb,
+			content,
+			renderFooter([]string{"<enter/y> build", "<ctrl+y> copy gh command", "<esc/b> back to form"}),
+		)
+	}
+
 	var sb strings.Builder
 	sb.WriteString("\n")
 
@@ -336,7 +553,9 @@ func (m model) viewDispatchForm() string {
 			labelText += " [required]"
 		}
 		var labelLine string
-		if active {
+		if active && noColor {
+			labelLine = "▶ " + labelText // bold is gone too without color
+		} else if active {
 			labelLine = "  " + styleHeader.Render(labelText)
 		} else {
 			labelLine = "  " + styleDim.Render(labelText)
@@ -347,13 +566,25 @@ func (m model) viewDispatchForm() string {
 		sb.WriteString(labelLine + "\n")
 
 		// Input widget
-		sb.WriteString("  " + f.input.View() + "\n")
+		if f.area != nil {
+			area := *f.area
+			if active {
+				area.Focus()
+			} else {
+				area.Blur()
+			}
+			for _, line := range strings.Split(area.View(), "\n") {
+				sb.WriteString("  " + line + "\n")
+			}
+		} else {
+			sb.WriteString("  " + f.input.View() + "\n")
+		}
 
 		// Ref field: section-tab browser (Input / Branches / Tags)
 		if i == 0 && active && (len(m.refBranches) > 0 || len(m.refTags) > 0) {
 			filter := strings.ToLower(f.input.Value())
-			fb := filterRefs(m.refBranches, filter)
-			ft := filterRefs(m.refTags, filter)
+			fb := filterRefs(m.refBranches, filter, m.config.RefMatch)
+			ft := filterRefs(m.refTags, filter, m.config.RefMatch)
 
 			// Section tab bar
 			hilite := lipgloss.NewStyle().Foreground(colorWhite).Bold(true)
@@ -378,13 +609,16 @@ func (m model) viewDispatchForm() string {
 
 			// List for the active section (1=branches, 2=tags)
 			const maxVisible = 6
-			var listRefs []string
+			var listRefs []refRow
 			var listIdx int
 			switch m.refSection {
 			case 1:
-				listRefs, listIdx = fb, m.refBranchIdx
+				listRefs, listIdx = m.branchRows(filter), m.refBranchIdx
 			case 2:
-				listRefs, listIdx = ft, m.refTagIdx
+				for _, t := range ft {
+					listRefs = append(listRefs, refRow{name: t})
+				}
+				listIdx = m.refTagIdx
 			}
 			if len(listRefs) == 0 && m.refSection != 0 {
 				sb.WriteString("  " + styleDim.Render("(no matches)") + "\n")
@@ -401,7 +635,16 @@ func (m model) viewDispatchForm() string {
 					end = len(listRefs)
 				}
 				for j := start; j < end; j++ {
-					opt := listRefs[j]
+					row := listRefs[j]
+					opt := row.name
+					switch {
+					case row.header && row.collapsed:
+						opt = fmt.Sprintf("▸ %s (%d)", row.name, row.count)
+					case row.header:
+						opt = fmt.Sprintf("▾ %s (%d)", row.name, row.count)
+					case row.member:
+						opt = "  " + row.name
+					}
 					if j == listIdx {
 						sb.WriteString("  " + lipgloss.NewStyle().
 							Background(colorSelected).
@@ -420,7 +663,7 @@ func (m model) viewDispatchForm() string {
 
 		// Hints for choice/boolean types
 		switch f.fieldType {
-		case "choice":
+		case "choice", "environment":
 			if len(f.options) > 0 {
 				var parts []string
 				for j, opt := range f.options {
@@ -434,6 +677,15 @@ func (m model) viewDispatchForm() string {
 			}
 		case "boolean":
 			sb.WriteString("  " + styleDim.Render("space / ↑↓  toggle") + "\n")
+		case "number":
+			if validNumber(f.input.Value()) {
+				sb.WriteString("  " + styleDim.Render("number") + "\n")
+			} else {
+				sb.WriteString("  " + styleError.Render("number — not a valid number") + "\n")
+			}
+		}
+		if f.fieldType == "environment" && active {
+			sb.WriteString(m.envVarsHint(f.input.Value()))
 		}
 
 		sb.WriteString("\n")
@@ -445,17 +697,20 @@ func (m model) viewDispatchForm() string {
 	btnFocus := lipgloss.NewStyle().Background(colorSelected).Foreground(colorWhite).Bold(true)
 	switch m.formButton {
 	case 1:
-		btnCancel = btnFocus.Render("  Cancel  ")
+		btnCancel = btnFocus.Render(focusMark("  Cancel  "))
 	case 2:
-		btnBuild = btnFocus.Render("  Build  ")
+		btnBuild = btnFocus.Render(focusMark("  Build  "))
 	}
 	sb.WriteString("  " + btnCancel + "   " + btnBuild + "\n")
 
 	var footerHints []string
 	if m.formButton != 0 {
-		footerHints = []string{"<←/→> switch", "<enter> confirm", "<tab> fields", "<esc> back"}
+		footerHints = []string{"<←/→> switch", "<enter> confirm", "<tab> fields", "<ctrl+y> copy gh command", "<esc> back"}
 	} else {
-		footerHints = []string{"<tab> next", "<←/→> section", "<↑/↓> navigate", "<enter> select", "<esc> back"}
+		footerHints = []string{"<tab> next", "<←/→> section", "<↑/↓> navigate", "<enter> select", "<ctrl+e> multi-line", "<ctrl+y> copy gh command", "<ctrl+r> defaults", "<esc> back"}
+		if m.formActiveField == 0 {
+			footerHints = slices.Insert(footerHints, 4, "<ctrl+l> reload refs")
+		}
 	}
 	footer := renderFooter(footerHints)
 
@@ -473,6 +728,32 @@ func (m model) viewDispatchForm() string {
 	)
 }
 
+// maxEnvVarLines bounds the variables listed under an environment input.
+const maxEnvVarLines = 5
+
+// envVarsHint lists the variables of the environment chosen in the dispatch
+// form, read-only. Nothing is shown while they load or when the token can't
+// read them.
+func (m model) envVarsHint(env string) string {
+	vars := m.envVars[env]
+	if len(vars) == 0 {
+		return ""
+	}
+	nameW := 0
+	for _, v := range vars[:min(len(vars), maxEnvVarLines)] {
+		nameW = max(nameW, len(v.Name))
+	}
+	var sb strings.Builder
+	sb.WriteString("  " + styleDim.Render("variables of "+env+":") + "\n")
+	for _, v := range vars[:min(len(vars), maxEnvVarLines)] {
+		sb.WriteString("    " + styleDim.Render(padRight(v.Name, nameW)+" = "+truncate(firstLine(v.Value), max(10, m.width-nameW-10))) + "\n")
+	}
+	if n := len(vars) - maxEnvVarLines; n > 0 {
+		sb.WriteString("    " + styleDim.Render(fmt.Sprintf("… %d more", n)) + "\n")
+	}
+	return sb.String()
+}
+
 // ─── Workflows view ───────────────────────────────────────────────────────────
 
 func (m model) viewWorkflows() string {
@@ -492,8 +773,8 @@ func (m model) viewWorkflows() string {
 	if m.statusMsg != "" {
 		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
 	} else {
-		ref := m.defaultBranch
-		if ref == "" {
+		ref := m.dispatchDefaultRef()
+		if m.defaultBranch == "" && ref == "main" {
 			ref = "…"
 		}
 		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(
@@ -504,15 +785,9 @@ func (m model) viewWorkflows() string {
 	colHeaders := m.workflowColHeaders()
 	listView := m.workflowsList.View()
 
-	ref := m.defaultBranch
-	if ref == "" {
-		ref = "main"
-	}
-	footer := renderFooter([]string{
-		"<enter> dispatch on " + ref,
-		"<esc/b> back",
-		"<q> quit",
-	})
+	ref := m.dispatchDefaultRef()
+	footer := renderFooter(append([]string{"<enter> dispatch on " + ref},
+		keyHints(workflowKeys, "/", "H", "?", "esc/b", "q")...))
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		appBar,
@@ -537,15 +812,112 @@ func (m model) workflowColHeaders() string {
 	return colHeaderStyle.Render("     " + file + " " + name)
 }
 
+// ─── Artifacts view ───────────────────────────────────────────────────────────
+
+func (m model) viewArtifacts() string {
+	var viewLabel string
+	switch {
+	case m.loading && len(m.artifactsList.Items()) == 0:
+		viewLabel = m.spinner.View() + " Loading artifacts…"
+	case m.loading:
+		viewLabel = m.spinner.View() + " Downloading…"
+	default:
+		viewLabel = fmt.Sprintf("Artifacts [%d]", len(m.artifactsList.Items()))
+	}
+	appBar := m.renderAppBar(viewLabel)
+
+	var breadcrumb string
+	if m.statusMsg != "" {
+		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
+	} else {
+		run := m.jobsViewRun()
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Actions › Runs › " + truncate(run.Name, m.width-40) + " › Artifacts")
+	}
+
+	nameW, sizeW, expiryW := artifactColumns(m.width)
+	colHeaders := colHeaderStyle.Render("  " +
+		padRight("NAME", nameW) + " " + padRight("SIZE", sizeW) + " " + truncate("EXPIRY", expiryW))
+
+	var listView string
+	if !m.loading && len(m.artifactsList.Items()) == 0 {
+		listView = styleDim.Render("\n  This run has no artifacts")
+		listView += strings.Repeat("\n", max(0, m.height-6))
+	} else {
+		listView = m.artifactsList.View()
+	}
+
+	footer := renderFooter(keyHints(artifactKeys, "enter", "esc/b", "q"))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		appBar,
+		breadcrumb,
+		colHeaders,
+		listView,
+		footer,
+	)
+}
+
+// ─── Dispatch history view ────────────────────────────────────────────────────
+
+func (m model) viewDispatchHistory() string {
+	viewLabel := fmt.Sprintf("Dispatch history [%d]", len(m.historyList.Items()))
+	if m.loading {
+		viewLabel = m.spinner.View() + " Dispatching…"
+	}
+	appBar := m.renderAppBar(viewLabel)
+
+	var breadcrumb string
+	if m.statusMsg != "" {
+		breadcrumb = styleDim.Width(m.width).Render(" " + m.statusMsg)
+	} else {
+		breadcrumb = breadcrumbDimStyle.Width(m.width).Render(" Actions › Runs › Dispatch › History  (made from this machine)")
+	}
+
+	ageW, wfW, refW, byW, inputsW := historyColumns(m.width, m.config.TimeFormat)
+	colHeaders := colHeaderStyle.Render("  " +
+		padRight("WHEN", ageW) + " " + padRight("WORKFLOW", wfW) + " " +
+		padRight("REF", refW) + " " + padRight("BY", byW) + " " + truncate("INPUTS", inputsW))
+
+	var listView string
+	if len(m.historyList.Items()) == 0 {
+		listView = styleDim.Render("\n  No dispatches made through tgh yet")
+		listView += strings.Repeat("\n", max(0, m.height-6))
+	} else {
+		listView = m.historyList.View()
+	}
+
+	footer := renderFooter(keyHints(historyKeys, "enter", "esc/b", "q"))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		appBar,
+		breadcrumb,
+		colHeaders,
+		listView,
+		footer,
+	)
+}
+
 // ─── Logs view ────────────────────────────────────────────────────────────────
 
+// stepLabel returns a step's name truncated to width, prefixed with its number
+// when step numbers are toggled on (to match numbered ##[group] markers).
+func (m model) stepLabel(s Step, width int) string {
+	if !m.stepNumbers {
+		return truncate(s.Name, width)
+	}
+	prefix := fmt.Sprintf("%d. ", s.Number)
+	return prefix + truncate(s.Name, max(1, width-len(prefix)))
+}
+
 func (m model) renderStepsContent() string {
 	steps := m.selectedJob.Steps
 	if len(steps) == 0 {
 		return "\n " + m.spinner.View() + " Waiting for steps…"
 	}
 
-	nameW := max(4, m.width-3)
+	// Durations are right-aligned in a column after the names.
+	const durW = 9
+	nameW := max(4, m.mainWidth()-4-durW)
 
 	var lines []string
 	for _, s := range steps {
@@ -556,21 +928,18 @@ func (m model) renderStepsContent() string {
 			icon = statusIcon(s.Status, s.Conclusion)
 		}
 
+		name := padRight(m.stepLabel(s, nameW), nameW)
+		dur := fmt.Sprintf("%*s", durW, stepDuration(s))
 		var line string
 		switch {
 		case s.Status == "in_progress":
-			elapsed := ""
-			if !s.StartedAt.IsZero() {
-				elapsed = " " + styleDim.Render("("+time.Since(s.StartedAt).Round(time.Second).String()+")")
-			}
-			label := styleHeader.Render(truncate(s.Name, nameW)) + elapsed
-			line = " " + icon + " " + label
+			line = " " + icon + " " + styleHeader.Render(name) + " " + dur
 		case s.Status == "completed" && s.Conclusion == "failure":
-			line = " " + icon + " " + styleError.Render(truncate(s.Name, nameW))
+			line = " " + icon + " " + styleError.Render(name) + " " + styleDim.Render(dur)
 		case s.Status == "completed":
-			line = " " + icon + " " + truncate(s.Name, nameW)
+			line = " " + icon + " " + name + " " + styleDim.Render(dur)
 		default:
-			line = " " + icon + " " + styleDim.Render(truncate(s.Name, nameW))
+			line = " " + icon + " " + styleDim.Render(name)
 		}
 		lines = append(lines, line)
 	}
@@ -597,13 +966,13 @@ func (m model) viewLogs() string {
 		for _, s := range m.selectedJob.Steps {
 			switch {
 			case s.Status == "completed" && s.Conclusion == "success":
-				dots.WriteString(statusSuccess.Render("●"))
+				dots.WriteString(statusSuccess.Render(icons.stepDone))
 			case s.Status == "completed" && (s.Conclusion == "failure" || s.Conclusion == "cancelled"):
-				dots.WriteString(statusFailure.Render("●"))
+				dots.WriteString(statusFailure.Render(icons.stepDone))
 			case s.Status == "completed":
-				dots.WriteString(statusNeutral.Render("●"))
+				dots.WriteString(statusNeutral.Render(icons.stepDone))
 			default:
-				dots.WriteString(styleDim.Render("○"))
+				dots.WriteString(styleDim.Render(icons.stepPending))
 			}
 		}
 		if dots.Len() > 0 {
@@ -620,12 +989,24 @@ func (m model) viewLogs() string {
 			if s.Status == "in_progress" {
 				dur := ""
 				if !s.StartedAt.IsZero() {
-					dur = " (" + time.Since(s.StartedAt).Round(time.Second).String() + ")"
+					dur = " (" + clockNow().Sub(s.StartedAt).Round(time.Second).String() + ")"
 				}
-				extras = "  " + styleDim.Render("▶ "+s.Name+dur)
+				extras = "  " + styleDim.Render("▶ "+m.stepLabel(s, m.width)+dur)
 				break
 			}
 		}
+		if m.followStep {
+			extras += "  " + styleAccent.Render("[follow step]")
+		}
+		if m.logPinned {
+			extras += "  " + styleAccent.Render("[pinned: "+truncate(m.logAnchorName, 30)+"]")
+		}
+		if m.logRaw == "" && m.logWaits > 0 {
+			extras += "  " + styleDim.Render("waiting for logs to become available…")
+		}
+		if m.collapseDupes {
+			extras += "  " + styleAccent.Render("[collapsed]")
+		}
 	} else {
 		if m.autoScroll {
 			extras += "  " + styleAccent.Render("[auto-scroll]")
@@ -633,6 +1014,34 @@ func (m model) viewLogs() string {
 		if m.logFilter != "" {
 			extras += "  " + styleAccent.Render("[filter: "+m.logFilter+"]")
 		}
+		if m.logSearch != "" && !m.logSearchMode {
+			extras += "  " + styleAccent.Render(fmt.Sprintf("[search: %s %s]", m.logSearch, m.searchPosition()))
+		}
+		if m.collapseDupes {
+			extras += "  " + styleAccent.Render("[collapsed]")
+		}
+	}
+	if m.showSummary {
+		extras += "  " + styleAccent.Render("[summary]")
+	}
+	if m.showDiff && m.diffLoaded {
+		label := "[vs previous run: no differences]"
+		if n := len(m.diffHunks); n > 0 {
+			label = fmt.Sprintf("[vs previous run: difference %d/%d]", m.diffHunkIdx+1, n)
+		}
+		extras += "  " + styleAccent.Render(label)
+	}
+	if m.plainLogs {
+		extras += "  " + styleAccent.Render("[no colors]")
+	}
+	if m.wrapEnabled {
+		extras += "  " + styleAccent.Render("[wrap]")
+	}
+	if m.showTimestamps && m.logTimestamped != "" {
+		extras += "  " + styleAccent.Render("[timestamps]")
+	}
+	if m.config.Redact.Enabled {
+		extras += "  " + styleWarn.Render("[redacted]")
 	}
 	if m.statusMsg != "" {
 		extras += "  " + styleAccent.Render(m.statusMsg)
@@ -647,16 +1056,37 @@ func (m model) viewLogs() string {
 	} else {
 		runBreadcrumb = " Run: " + truncate(m.selectedRun.Name, m.width-8)
 	}
+	runBreadcrumb += runOrigin(m.selectedRun, m.width-lipgloss.Width(runBreadcrumb)-1)
 	runLine := breadcrumbDimStyle.Render(runBreadcrumb)
+	if m.compactHeader {
+		// One row: status and current step, then the run; cut at the edge.
+		statusLine = lipgloss.NewStyle().MaxWidth(m.width).Render(
+			statusLine + "  " + breadcrumbDimStyle.Render("·"+runBreadcrumb))
+	}
 
+	// Running jobs show the steps panel until streamed log output arrives.
 	var content string
-	if isRunning(m.selectedJob.Status) {
+	if m.showSummary {
+		if m.summaryLoaded {
+			content = m.summaryViewport.View()
+		} else {
+			content = "\n " + m.spinner.View() + " Loading job summary…"
+		}
+	} else if m.showDiff {
+		if m.diffLoaded {
+			content = logDiffHeader(m.diffPrevRun, m.diffPrevJob, m.diffViewport.Width, m.config.TimeFormat) +
+				"\n" + m.diffViewport.View()
+		} else {
+			content = "\n " + m.spinner.View() + " Finding this job in an earlier run…"
+		}
+	} else if isRunning(m.selectedJob.Status) && m.logRaw == "" {
 		content = m.renderStepsContent()
 	} else if !m.logLoaded {
 		content = "\n " + m.spinner.View() + " Loading logs…"
 	} else {
 		content = m.logViewport.View()
 	}
+	content = m.withSidebar(content)
 
 	var filterBar string
 	if m.logFilterMode {
@@ -672,61 +1102,279 @@ func (m model) viewLogs() string {
 			}
 			countStr = styleDim.Render(fmt.Sprintf("  (%d lines)", count))
 		}
-		filterBar = filterBarStyle.Width(m.width).Render("  / " + m.logFilter + cursor + countStr)
+		filterBar = filterBarStyle.Width(m.width).Render("  & " + m.logFilter + cursor + countStr)
+	}
+	if m.logSearchMode {
+		cursor := styleAccent.Render("█")
+		countStr := ""
+		if m.logSearch != "" {
+			countStr = styleDim.Render("  (" + m.searchPosition() + ")")
+		}
+		filterBar = filterBarStyle.Width(m.width).Render("  / " + m.logSearch + cursor + countStr)
 	}
 
 	var footerHints []string
 	switch {
+	case m.showSummary:
+		footerHints = []string{"<↑/↓> scroll", "<S/esc> back to log", "<q> quit"}
+	case m.showDiff:
+		footerHints = keyHints(diffKeys, "↑/↓", "]/[", "V/esc", "q")
 	case m.logFilterMode:
 		footerHints = []string{"<esc> clear filter", "<enter> close bar", "<↑/↓> scroll"}
+	case m.logSearchMode:
+		footerHints = []string{"<esc> clear search", "<enter> close bar", "<↑/↓> prev/next match"}
+	case isRunning(m.selectedJob.Status) && m.logRaw != "":
+		footerHints = keyHints(logsKeys, "↑/↓", "a", "f", "p", "tab", "space", "D", "M", "ctrl+w", "H", "s", "o", "r", "R", "?", "esc/b", "q")
 	case isRunning(m.selectedJob.Status):
-		footerHints = []string{"<o> open", "<r> refresh", "<esc/b> back", "<q> quit"}
+		footerHints = keyHints(logsKeys, "#", "o", "r", "R", "?", "esc/b", "q")
 	default:
-		footerHints = []string{
-			"<↑/↓> scroll", "<g> top", "<G> bottom", "<a> auto-scroll",
-			"</> filter", "<c> copy", "<o> open", "<r> refresh", "<esc/b> back", "<q> quit",
+		keys := []string{"↑/↓", "g", "G", "a", "/", "n/N", "e", "tab", "space", "&", "D", "M", "ctrl+w", "t", "c/C", "F", "E", "J", "S", "V", "H", "s", "o"}
+		if _, ok := failedStep(m.selectedJob); ok {
+			keys = append(keys, "O")
 		}
+		footerHints = keyHints(logsKeys, append(keys, "r", "?", "esc/b", "q")...)
 	}
 	footer := renderFooter(footerHints)
 
 	parts := []string{appBar, statusLine, runLine, content}
-	if m.logFilterMode {
+	if m.compactHeader {
+		parts = []string{appBar, statusLine, content}
+	}
+	if m.logFilterMode || m.logSearchMode {
 		parts = append(parts, filterBar)
 	}
 	parts = append(parts, footer)
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
+// searchPosition describes the current search match, e.g. "3/12".
+func (m model) searchPosition() string {
+	if len(m.searchMatches) == 0 {
+		return "no matches"
+	}
+	return fmt.Sprintf("%d/%d", m.searchIdx+1, len(m.searchMatches))
+}
+
+// ─── Run sidebar ──────────────────────────────────────────────────────────────
+
+const (
+	sidebarWidth    = 34  // including the separator column
+	sidebarMinWidth = 140 // narrower terminals never show the sidebar
+)
+
+// sidebarVisible reports whether the run sidebar is shown next to the jobs and
+// logs views. It is on by default once the terminal is wide enough.
+func (m model) sidebarVisible() bool {
+	return !m.sidebarHidden && m.width >= sidebarMinWidth
+}
+
+// mainWidth is the width available to the main content, excluding the sidebar.
+func (m model) mainWidth() int {
+	if m.sidebarVisible() {
+		return m.width - sidebarWidth
+	}
+	return m.width
+}
+
+// withSidebar places the recent-runs sidebar to the right of content.
+func (m model) withSidebar(content string) string {
+	if !m.sidebarVisible() {
+		return content
+	}
+	height := lipgloss.Height(content)
+	main := lipgloss.NewStyle().Width(m.mainWidth()).MaxWidth(m.mainWidth()).Render(content)
+	return lipgloss.JoinHorizontal(lipgloss.Top, main, m.renderRunSidebar(height))
+}
+
+// renderRunSidebar lists the most recent runs with their status so the broader
+// run list stays in view while drilling into a single run.
+func (m model) renderRunSidebar(height int) string {
+	nameW := sidebarWidth - 6
+	lines := []string{colHeaderStyle.Render(" RECENT RUNS")}
+	for _, item := range m.runsList.Items() {
+		if len(lines) >= height {
+			break
+		}
+		ri, ok := item.(runItem)
+		if !ok {
+			continue
+		}
+		name := truncate(ri.run.Name, nameW)
+		if ri.run.ID == m.selectedRun.ID {
+			name = styleHeader.Render(name)
+		} else {
+			name = styleDim.Render(name)
+		}
+		lines = append(lines, " "+statusIcon(ri.run.Status, ri.run.Conclusion)+" "+name)
+	}
+	return lipgloss.NewStyle().
+		Width(sidebarWidth - 1).
+		Height(height).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(colorGray).
+		Render(strings.Join(lines, "\n"))
+}
+
 // ─── Log rendering ────────────────────────────────────────────────────────────
 
-func renderLogs(content string) string {
+// renderLogs styles log lines for the viewport. Colors the job printed are
+// kept unless plain is set; occurrences of search are highlighted. With a
+// wrap width, long lines are hard-wrapped and rows holds the row each line
+// starts on; it is nil otherwise.
+func renderLogs(content string, plain bool, search string, wrap int) (rendered string, rows []int) {
+	var match *regexp.Regexp
+	if search != "" {
+		match = regexp.MustCompile("(?i)" + regexp.QuoteMeta(search))
+	}
 	lines := strings.Split(content, "\n")
 	result := make([]string, len(lines))
+	if wrap > 0 {
+		rows = make([]int, len(lines))
+	}
+	row := 0
 	for i, line := range lines {
-		result[i] = renderLogLine(line)
+		result[i] = renderLogLine(sanitizeLogLine(normalizeANSI(line, plain)), match)
+		if wrap > 0 {
+			rows[i] = row
+			result[i] = wrapANSI(result[i], wrap)
+			row += strings.Count(result[i], "\n") + 1
+		}
+	}
+	return strings.Join(result, "\n"), rows
+}
+
+// wrapANSI hard-wraps a styled line at width. The colors active at each break
+// are closed at the end of the row and reopened on the next, so every row
+// carries its own styling.
+func wrapANSI(line string, width int) string {
+	if lipgloss.Width(line) <= width {
+		return line
+	}
+	parts := strings.Split(ansi.Hardwrap(line, width, true), "\n")
+	active := ""
+	for i, p := range parts {
+		out := active + p
+		for _, seq := range ansiEscape.FindAllString(p, -1) {
+			switch {
+			case seq == ansiReset || seq == "\x1b[m":
+				active = ""
+			case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+				active += seq
+			}
+		}
+		if active != "" {
+			out += ansiReset
+		}
+		parts[i] = out
+	}
+	return strings.Join(parts, "\n")
+}
+
+var (
+	// ansiEscape matches CSI sequences (colors, cursor movement, erase), OSC
+	// sequences (titles, hyperlinks) and the remaining two-byte escapes.
+	ansiEscape = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+	ansiReset  = "\x1b[0m"
+)
+
+// normalizeANSI keeps a line's SGR (color) sequences and drops every other
+// escape, which would move the cursor or rewrite the screen in the viewport.
+// A line that leaves a color open is reset at its end so it can't bleed into
+// the next. plain drops colors as well.
+func normalizeANSI(line string, plain bool) string {
+	if !strings.Contains(line, "\x1b") {
+		return line
+	}
+	colored := false
+	line = ansiEscape.ReplaceAllStringFunc(line, func(seq string) string {
+		if plain || !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			return ""
+		}
+		colored = true
+		return seq
+	})
+	if colored && !strings.HasSuffix(line, ansiReset) && !strings.HasSuffix(line, "\x1b[m") {
+		line += ansiReset
 	}
-	return strings.Join(result, "\n")
+	return line
 }
 
-func renderLogLine(line string) string {
+// stripANSI removes all escape sequences from s.
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// invalidBytePlaceholder stands in for each run of invalid UTF-8 bytes or
+// control characters when rendering logs.
+const invalidBytePlaceholder = "\uFFFD"
+
+// sanitizeLogLine makes a log line safe to draw: runs of invalid UTF-8 (binary
+// output, latin-1) collapse into one placeholder, and control characters that
+// could move the cursor or garble the terminal are replaced, C1 controls
+// (such as the 8-bit CSI) included. Of a line redrawn with carriage returns,
+// as progress bars do, only the last version is kept. Tabs and ANSI escape
+// sequences are kept. The raw log is not modified, so copying still yields
+// the original bytes.
+func sanitizeLogLine(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.ToValidUTF8(line, invalidBytePlaceholder)
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\x1b' || (r >= 0x20 && r != 0x7f && (r < 0x80 || r > 0x9f)) {
+			return r
+		}
+		return '\uFFFD'
+	}, line)
+}
+
+// renderLogLine applies the styling of workflow commands (##[group],
+// ##[error], ...). Their own colors are dropped so a reset inside the message
+// doesn't cut the style short; other lines are left as they are. Text matching
+// match, if set, is highlighted.
+func renderLogLine(line string, match *regexp.Regexp) string {
+	if plain := stripANSI(line); strings.HasPrefix(plain, "##[") {
+		line = plain
+	}
+	highlight := func(s string) string {
+		if match == nil {
+			return s
+		}
+		// Only the text between escape sequences is searched, so a query
+		// like "31" can't match inside a color code.
+		var b strings.Builder
+		last := 0
+		hl := func(text string) string {
+			return match.ReplaceAllStringFunc(text, matchMark)
+		}
+		for _, loc := range ansiEscape.FindAllStringIndex(s, -1) {
+			b.WriteString(hl(s[last:loc[0]]))
+			b.WriteString(s[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		b.WriteString(hl(s[last:]))
+		return b.String()
+	}
 	var rendered string
 	switch {
 	case strings.HasPrefix(line, "##[group]"):
 		name := strings.TrimPrefix(line, "##[group]")
-		rendered = styleAccent.Render("▶ " + name)
+		rendered = styleAccent.Render("▶ " + highlight(name))
 	case strings.HasPrefix(line, "##[endgroup]"):
 		rendered = styleDim.Render(strings.Repeat("─", 60))
 	case strings.HasPrefix(line, "##[error]"):
 		msg := strings.TrimPrefix(line, "##[error]")
-		rendered = styleError.Render("✗ " + msg)
+		rendered = styleError.Render("✗ " + highlight(msg))
 	case strings.HasPrefix(line, "##[warning]"):
 		msg := strings.TrimPrefix(line, "##[warning]")
-		rendered = styleWarn.Render("⚠ " + msg)
+		rendered = styleWarn.Render("⚠ " + highlight(msg))
 	case strings.HasPrefix(line, "##[command]"):
 		msg := strings.TrimPrefix(line, "##[command]")
-		rendered = styleCmd.Render("$ " + msg)
+		rendered = styleCmd.Render("$ " + highlight(msg))
 	default:
-		rendered = line
+		rendered = highlight(line)
 	}
 	return rendered
 }
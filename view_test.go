@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestSanitizeLogLine(t *testing.T) {
+	tests := []struct {
+		name, line, want string
+	}{
+		{"plain", "hello world", "hello world"},
+		{"tab and color kept", "a\tb \x1b[31mred\x1b[0m", "a\tb \x1b[31mred\x1b[0m"},
+		{"progress redraws", "progress 50%\rprogress 100%\r", "progress 100%"},
+		{"carriage return in the middle", "downloading\rdone", "done"},
+		{"CRLF ending", "line\r", "line"},
+		{"C0 control", "bell\a here", "bell� here"},
+		{"DEL", "a\x7fb", "a�b"},
+		{"8-bit CSI", "a\u009b2Jb", "a�2Jb"},
+		{"C1 range ends", "\u0080x\u009f", "�x�"},
+		{"latin-1 letters kept", "café  ok", "café  ok"},
+		{"invalid UTF-8", "a\xff\xfeb", "a�b"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeLogLine(tt.line); got != tt.want {
+			t.Errorf("%s: sanitizeLogLine(%q) = %q, want %q", tt.name, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestRenderLogsColors(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		plain bool
+		want  string
+	}{
+		{"colored", "\x1b[31mred\x1b[0m", false, "\x1b[31mred\x1b[0m"},
+		{"colored, reset added", "\x1b[31mred\x1b[0m after", false, "\x1b[31mred\x1b[0m after\x1b[0m"},
+		{"plain", "\x1b[31mred\x1b[0m", true, "red"},
+		{"cursor movement dropped", "\x1b[2Kred", false, "red"},
+	}
+	for _, tt := range tests {
+		if got, _ := renderLogs(tt.line, tt.plain, "", 0); got != tt.want {
+			t.Errorf("%s: renderLogs(%q) = %q, want %q", tt.name, tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxWatchFailures is how many polls in a row may fail before --watch gives
+// up on the run.
+const maxWatchFailures = 5
+
+// WatchRun polls a run every interval until it completes and returns it as
+// completed. onUpdate is called with every poll's state of the run.
+func (c *GitHubClient) WatchRun(runID int64, interval time.Duration, onUpdate func(WorkflowRun)) (WorkflowRun, error) {
+	failures := 0
+	for {
+		run, err := c.GetRun(runID)
+		switch {
+		case err != nil:
+			failures++
+			if failures >= maxWatchFailures {
+				return run, err
+			}
+			dbg("WatchRun %d: %v", runID, err)
+		case run.Status == "completed":
+			onUpdate(run)
+			return run, nil
+		default:
+			failures = 0
+			onUpdate(run)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// watchRun is tgh --watch: it reports the run's progress on stdout until the
+// run completes, then rings the bell, shows a desktop notification and
+// returns the exit code, 0 when the run succeeded.
+func watchRun(c *GitHubClient, runID int64, interval time.Duration) int {
+	lastStatus := ""
+	run, err := c.WatchRun(runID, interval, func(run WorkflowRun) {
+		if lastStatus == "" {
+			fmt.Printf("Watching %s on %s\n  %s\n", run.Name, run.HeadBranch, run.HTMLURL)
+		}
+		if run.Status != lastStatus {
+			lastStatus = run.Status
+			fmt.Printf("%s  %s %s\n", time.Now().Format("15:04:05"),
+				statusIcon(run.Status, run.Conclusion), statusLabel(run.Status, run.Conclusion))
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+
+	jobs, err := c.ListJobs(run.ID)
+	if err != nil {
+		dbg("ListJobs %d: %v", run.ID, err)
+	}
+	summary := watchSummary(run, jobs)
+	title := run.Name + " on " + run.HeadBranch
+	fmt.Print("\a")
+	fmt.Println()
+	fmt.Println(styleHeader.Render(fmt.Sprintf("%s %s %s after %s",
+		statusIcon(run.Status, run.Conclusion), title, strings.ToUpper(statusLabel(run.Status, run.Conclusion)), runDuration(run))))
+	fmt.Println("  " + summary)
+	if err := notify(title, summary); err != nil {
+		dbg("desktop notification: %v", err)
+	}
+	if run.Conclusion != "success" {
+		return 1
+	}
+	return 0
+}
+
+// watchSummary describes a completed run by its conclusion, which also
+// decides --watch's exit code, and names the jobs that failed, e.g.
+// "✗ run failed: test (ubuntu), deploy". A job allowed to fail by
+// continue-on-error is still named, but doesn't turn a success into a failure.
+// The jobs view announces a run it saw finish with it too.
+func watchSummary(run WorkflowRun, jobs []Job) string {
+	outcome := strings.ReplaceAll(statusLabel(run.Status, run.Conclusion), "_", " ")
+	switch run.Conclusion {
+	case "success":
+		outcome = "succeeded"
+	case "failure":
+		outcome = "failed"
+	}
+	summary := getPlainStatusIcon(run.Status, run.Conclusion) + " run " + outcome
+	if names := failedJobNames(jobs); names != "" {
+		summary += ": " + names
+	}
+	return summary
+}
+
+// notify shows a desktop notification.
+func notify(title, message string) error {
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = "osascript"
+		args = []string{"-e", fmt.Sprintf("display notification %q with title %q", message, title)}
+	case "windows":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		cmd = "powershell"
+		args = []string{"-NoProfile", "-Command",
+			"Add-Type -AssemblyName System.Windows.Forms; " +
+				"$n = New-Object System.Windows.Forms.NotifyIcon; " +
+				"$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; " +
+				"$n.ShowBalloonTip(10000, " + quote(title) + ", " + quote(message) + ", 'Info'); " +
+				"Start-Sleep -Seconds 10; $n.Dispose()"}
+	case "linux":
+		cmd = "notify-send"
+		args = []string{title, message}
+	default:
+		return fmt.Errorf("unsupported platform")
+	}
+
+	c := exec.Command(cmd, args...)
+	if runtime.GOOS == "windows" {
+		// PowerShell runs for as long as the balloon shows; don't hold up the
+		// exit status for it.
+		return c.Start()
+	}
+	return c.Run()
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestWatchSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		run  WorkflowRun
+		jobs []Job
+		want string
+	}{
+		{
+			name: "success",
+			run:  WorkflowRun{Status: "completed", Conclusion: "success"},
+			jobs: []Job{{Name: "build", Conclusion: "success"}},
+			want: "✓ run succeeded",
+		},
+		{
+			// The run's conclusion wins over jobs that all passed.
+			name: "cancelled run without failed jobs",
+			run:  WorkflowRun{Status: "completed", Conclusion: "cancelled"},
+			jobs: []Job{{Name: "build", Conclusion: "success"}},
+			want: "⊘ run cancelled",
+		},
+		{
+			name: "action required",
+			run:  WorkflowRun{Status: "completed", Conclusion: "action_required"},
+			want: "○ run action required",
+		},
+		{
+			// A continue-on-error job failed, but the run succeeded.
+			name: "success with a failed job",
+			run:  WorkflowRun{Status: "completed", Conclusion: "success"},
+			jobs: []Job{{Name: "build", Conclusion: "success"}, {Name: "lint", Conclusion: "failure"}},
+			want: "✓ run succeeded: lint",
+		},
+		{
+			name: "failure",
+			run:  WorkflowRun{Status: "completed", Conclusion: "failure"},
+			jobs: []Job{{Name: "test", Conclusion: "failure"}, {Name: "deploy", Conclusion: "timed_out"}},
+			want: "✗ run failed: test, deploy",
+		},
+	}
+	for _, tt := range tests {
+		if got := watchSummary(tt.run, tt.jobs); got != tt.want {
+			t.Errorf("%s: watchSummary = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}